@@ -3,26 +3,53 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 
 	"phatic_dialogue/engine"
+	"phatic_dialogue/types"
 )
 
+// Config is the bot persona shown in the CLI.
+type Config struct {
+	Name       string
+	Prompt     string
+	UserPrompt string
+	Welcome    string
+	Goodbye    string
+	// Unavailable is the reply when the dialogue content could not be loaded.
+	Unavailable string
+	// Apology is the reply when the answer could not be built from the dialogue content.
+	Apology string
+}
+
 type CLI struct {
 	analyser *engine.Analyser
 	builder  *engine.Builder
+	config   Config
+	logger   *slog.Logger
+
+	// explanations are traces of the sentences of the latest input as they were answered, /why prints them.
+	explanations []engine.Explanation
 }
 
-func NewCLI(analyser *engine.Analyser, builder *engine.Builder) *CLI {
+func NewCLI(analyser *engine.Analyser, builder *engine.Builder, logger *slog.Logger, config Config) *CLI {
 	return &CLI{
 		analyser: analyser,
 		builder:  builder,
+		config:   config,
+		logger:   logger,
 	}
 }
 
 func (cli *CLI) Run(ctx context.Context) error {
-	fmt.Println("WELCOME TO PHATIC-DIALOGUE PROGRAM")
+	fmt.Println(cli.config.Welcome)
+
+	in := bufio.NewReader(os.Stdin)
 
 	for {
 		select {
@@ -31,20 +58,134 @@ func (cli *CLI) Run(ctx context.Context) error {
 		default:
 		}
 
-		fmt.Print("you>> ")
-		in := bufio.NewReader(os.Stdin)
+		fmt.Print(cli.config.UserPrompt)
 		sentence, err := in.ReadString('\n')
-		if err != nil {
+		if errors.Is(err, io.EOF) && sentence == "" {
+			// the input is closed, e.g. by CTRL+D.
+			fmt.Println()
+			fmt.Println(cli.config.Goodbye)
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
 			return err
 		}
 
-		sentence = sentence[:len(sentence)-1]
+		sentence = strings.TrimRight(sentence, "\r\n")
 
 		if sentence == "/q" || sentence == "\\q" || sentence == "quit" {
-			fmt.Println("BYE-BYE")
+			fmt.Println(cli.config.Goodbye)
 			return nil
 		}
 
-		fmt.Println("ms.X>> ", cli.builder.MakeAnswer(ctx, cli.analyser.AnalyseTopics(ctx, sentence)))
+		if sentence == "/why" {
+			cli.why(os.Stdout)
+			continue
+		}
+
+		fmt.Println(cli.config.Name+cli.config.Prompt, cli.reply(ctx, sentence))
 	}
 }
+
+// reply answers the sentence, or apologises and logs the error if it could not be answered.
+// The trace of the analysis is kept for /why.
+func (cli *CLI) reply(ctx context.Context, sentence string) string {
+	explanations, err := cli.analyser.ExplainInput(ctx, sentence)
+	cli.explanations = explanations
+	if err == nil {
+		analyses := make([][]types.Match, 0, len(explanations))
+		for _, explanation := range explanations {
+			analyses = append(analyses, explanation.Matches)
+		}
+
+		var reply string
+		reply, err = cli.builder.MakeReply(ctx, analyses)
+		if err == nil {
+			return reply
+		}
+	}
+
+	cli.logger.Error("sentence is not answered", "sentence", sentence, "error", err)
+	if errors.Is(err, engine.ErrStorageUnavailable) {
+		return cli.config.Unavailable
+	}
+
+	return cli.config.Apology
+}
+
+// why prints how the sentences of the latest input which were answered were matched.
+func (cli *CLI) why(w io.Writer) {
+	if len(cli.explanations) == 0 {
+		fmt.Fprintln(w, "nothing to explain yet")
+		return
+	}
+
+	for _, explanation := range cli.explanations {
+		fmt.Fprintf(w, "sentence:   %s\n", explanation.Input)
+		fmt.Fprintf(w, "normalized: %s\n", explanation.Normalized)
+		if explanation.Corrected != "" {
+			fmt.Fprintf(w, "corrected:  %s\n", explanation.Corrected)
+		}
+
+		if len(explanation.Candidates) == 0 {
+			fmt.Fprintln(w, "no template matches")
+		}
+		for _, candidate := range explanation.Candidates {
+			match := candidate.Match
+			fmt.Fprintf(w, "  %4d %-20s %q%s\n", match.Score, match.Topic, match.Template.Template, flags(candidate))
+			fmt.Fprintf(w, "       pattern: %s\n", candidate.Pattern)
+			fmt.Fprintf(w, "       span:    %q%s\n", candidate.Span, slots(match.Slots))
+		}
+
+		best := explanation.Matches[0]
+		switch {
+		case best.Confidence > 0:
+			fmt.Fprintf(w, "topic: %s, classified with confidence %.2f\n", best.Topic, best.Confidence)
+		case best.Topic == types.UnknownTopic:
+			fmt.Fprintf(w, "topic: %s\n", best.Topic)
+		default:
+			fmt.Fprintf(w, "topic: %s, template %q\n", best.Topic, best.Template.Template)
+		}
+	}
+}
+
+// flags describes how the candidate template matched.
+func flags(candidate engine.Candidate) string {
+	var flags []string
+	if candidate.Match.Fuzzy {
+		flags = append(flags, "fuzzy")
+	}
+	if candidate.Match.Negated {
+		flags = append(flags, "negated from "+string(candidate.Match.Template.Topic))
+	}
+	if candidate.Suppressed {
+		flags = append(flags, "suppressed by negation")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+// slots describes values captured by template slots.
+func slots(slots []types.Slot) string {
+	var values []string
+	for i, slot := range slots {
+		name := slot.Name
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+
+		value := fmt.Sprintf("%s=%q", name, slot.Value)
+		if slot.Canonical != "" {
+			value += fmt.Sprintf(" (%s)", slot.Canonical)
+		}
+
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	return ", slots: " + strings.Join(values, ", ")
+}
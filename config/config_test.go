@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoadFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		env   map[string]string
+		check func(config Config) bool
+	}{
+		{name: "defaults", check: func(c Config) bool { return c.Fuzzy && c.Classifier.Threshold == 0.5 }},
+		{name: "bare bool", args: []string{"--fuzzy"}, env: map[string]string{"PHATIC_FUZZY": "false"}, check: func(c Config) bool { return c.Fuzzy }},
+		{name: "bool value", args: []string{"--fuzzy=false"}, check: func(c Config) bool { return !c.Fuzzy }},
+		{name: "env", env: map[string]string{"PHATIC_FUZZY": "false", "PHATIC_DATABASE_MAX_OPEN_CONNS": "3"}, check: func(c Config) bool { return !c.Fuzzy && c.Database.MaxOpenConns == 3 }},
+		{name: "int", args: []string{"--max-open-conns", "3"}, check: func(c Config) bool { return c.Database.MaxOpenConns == 3 }},
+		{name: "int64", args: []string{"--random-seed=42"}, check: func(c Config) bool { return c.RandomSeed == 42 }},
+		{name: "float", args: []string{"--classifier-threshold", "0.7"}, check: func(c Config) bool { return c.Classifier.Threshold == 0.7 }},
+		{name: "string", args: []string{"--stemmer", "none"}, check: func(c Config) bool { return c.Stemmer == "none" }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			RegisterFlags(flags)
+			if err := flags.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := Load("", func(key string) (string, bool) {
+				value, ok := test.env[key]
+				return value, ok
+			}, flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !test.check(config) {
+				t.Errorf("unexpected config %+v", config)
+			}
+		})
+	}
+}
+
+func TestRegisterFlagsTypes(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags(flags)
+
+	types := map[string]string{
+		"fuzzy":                "bool",
+		"max-open-conns":       "int",
+		"random-seed":          "int64",
+		"classifier-threshold": "float64",
+		"database":             "string",
+	}
+	for name, want := range types {
+		if got := flags.Lookup(name).Value.Type(); got != want {
+			t.Errorf("--%s is %s, want %s", name, got, want)
+		}
+	}
+
+	if err := flags.Parse([]string{"--max-open-conns", "many"}); err == nil {
+		t.Error("invalid int flag is parsed")
+	}
+}
+
+func TestRegisterFlagsMasked(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags(flags)
+
+	defaults := Default()
+	if shown := flags.Lookup("database").DefValue; shown != defaults.Masked().Database.URL || shown == defaults.Database.URL {
+		t.Errorf("--database default = %q, want the password masked", shown)
+	}
+
+	config, err := Load("", func(string) (string, bool) { return "", false }, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Database.URL != defaults.Database.URL {
+		t.Errorf("database url = %q, want %q", config.Database.URL, defaults.Database.URL)
+	}
+}
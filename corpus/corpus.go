@@ -0,0 +1,226 @@
+// Package corpus reads and writes dialogue content files.
+//
+// A corpus file is a YAML (.yaml, .yml) or JSON (.json) document with a list of topics
+// and an optional list of entities:
+//
+//	topics:
+//	  - topic: "привітання"
+//	    negation: suppress
+//	    templates: ["привіт", {template: "вітаю", mode: whole}]
+//	    answers: ["привіт $", "вітаю $"]
+//	    singleInserts: []
+//	    groupInserts: [" , чим я можу вам допомогти ?"]
+//	    examples: ["добридень усім", "здоровенькі були"]
+//	entities:
+//	  - entity: "city"
+//	    values:
+//	      - {value: "київ", synonyms: ["києві", "kyiv"]}
+//
+// templates recognise the topic in user sentences, they are either strings or objects
+// with the template options, answers are replies to it,
+// while singleInserts and groupInserts replace "_" and "$" in its answers.
+// negation is either suppress, so that negated sentences do not match templates of the topic,
+// or the counterpart topic of negated sentences, it is set for a single template as well.
+// examples are optional sentences of the topic the classifier learns from.
+// entities are named lists of canonical values with their synonyms, referenced by templates as @city.
+package corpus
+
+import (
+	"bytes"
+	_ "embed" // embedding default corpus.
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"gopkg.in/yaml.v3"
+
+	"phatic_dialogue/types"
+)
+
+var (
+	// Error is the default corpus error class.
+	Error = errs.Class("corpus error")
+	// ErrUnknownFormat indicates that file extension is neither yaml nor json.
+	ErrUnknownFormat = errors.New("unknown corpus file format")
+	// ErrNoTopic indicates that corpus contains topic without name.
+	ErrNoTopic = errors.New("topic name is empty")
+	// ErrNoEntity indicates that corpus contains entity without name or value.
+	ErrNoEntity = errors.New("entity name or value is empty")
+)
+
+//go:embed default.yaml
+var defaultCorpus []byte
+
+// Default returns the corpus shipped with the program.
+func Default() (types.Corpus, error) {
+	return Parse(defaultCorpus, ".yaml")
+}
+
+// Load reads the corpus file.
+func Load(path string) (types.Corpus, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.Corpus{}, Error.Wrap(err)
+	}
+
+	corpus, err := parse(data, filepath.Ext(path))
+	if err != nil {
+		return types.Corpus{}, Error.New("%s: %v", path, err)
+	}
+
+	return corpus, nil
+}
+
+// LoadDir reads all corpus files of the directory in the name order,
+// merging contents of the same topic.
+func LoadDir(dir string) (types.Corpus, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return types.Corpus{}, Error.Wrap(err)
+	}
+
+	var corpora []types.Corpus
+	for _, entry := range entries {
+		if entry.IsDir() || !isCorpusFile(entry.Name()) {
+			continue
+		}
+
+		corpus, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return types.Corpus{}, err
+		}
+
+		corpora = append(corpora, corpus)
+	}
+
+	return Merge(corpora...), nil
+}
+
+// Parse decodes the corpus from the corpus file data of given extension.
+func Parse(data []byte, ext string) (types.Corpus, error) {
+	corpus, err := parse(data, ext)
+
+	return corpus, Error.Wrap(err)
+}
+
+// parse decodes the corpus from the corpus file data of given extension.
+func parse(data []byte, ext string) (types.Corpus, error) {
+	var file Corpus
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return types.Corpus{}, err
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return types.Corpus{}, err
+		}
+	default:
+		return types.Corpus{}, fmt.Errorf("%w %q", ErrUnknownFormat, ext)
+	}
+
+	corpus := types.Corpus{Contents: make([]types.Content, 0, len(file.Topics))}
+	for i, topic := range file.Topics {
+		if len(topic.Topic) == 0 {
+			return types.Corpus{}, fmt.Errorf("topic #%d: %w", i+1, ErrNoTopic)
+		}
+
+		corpus.Contents = append(corpus.Contents, topic.toContent())
+	}
+
+	for i, entity := range file.Entities {
+		entities, err := entity.toEntities()
+		if err != nil {
+			return types.Corpus{}, fmt.Errorf("entity #%d: %w", i+1, err)
+		}
+
+		corpus.Entities = append(corpus.Entities, entities...)
+	}
+
+	return corpus, nil
+}
+
+// Save writes the corpus into the corpus file, format is chosen by the file extension.
+func Save(path string, corpus types.Corpus) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, Error.Wrap(file.Close()))
+	}()
+
+	return Encode(file, corpus, filepath.Ext(path))
+}
+
+// Encode writes the corpus as the corpus file data of given extension.
+func Encode(w io.Writer, corpus types.Corpus, ext string) error {
+	file := Corpus{Topics: make([]Topic, 0, len(corpus.Contents)), Entities: fromEntities(corpus.Entities)}
+	for _, content := range corpus.Contents {
+		file.Topics = append(file.Topics, fromContent(content))
+	}
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(file); err != nil {
+			return Error.Wrap(err)
+		}
+
+		return Error.Wrap(encoder.Close())
+	case ".json":
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		return Error.Wrap(encoder.Encode(file))
+	default:
+		return Error.Wrap(fmt.Errorf("%w %q", ErrUnknownFormat, ext))
+	}
+}
+
+// Merge joins corpora, merging contents of the same topic keeping the first appearance order.
+func Merge(corpora ...types.Corpus) types.Corpus {
+	var merged types.Corpus
+	indexes := make(map[types.Topic]int)
+	for _, corpus := range corpora {
+		for _, content := range corpus.Contents {
+			i, ok := indexes[content.Topic]
+			if !ok {
+				indexes[content.Topic] = len(merged.Contents)
+				merged.Contents = append(merged.Contents, content)
+				continue
+			}
+
+			merged.Contents[i].Templates = append(merged.Contents[i].Templates, content.Templates...)
+			merged.Contents[i].Answers = append(merged.Contents[i].Answers, content.Answers...)
+			merged.Contents[i].SingleInserts = append(merged.Contents[i].SingleInserts, content.SingleInserts...)
+			merged.Contents[i].GroupInserts = append(merged.Contents[i].GroupInserts, content.GroupInserts...)
+			merged.Contents[i].Examples = append(merged.Contents[i].Examples, content.Examples...)
+		}
+
+		merged.Entities = append(merged.Entities, corpus.Entities...)
+	}
+
+	return merged
+}
+
+// isCorpusFile reports whether the file has corpus extension.
+func isCorpusFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
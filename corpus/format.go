@@ -0,0 +1,257 @@
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"phatic_dialogue/types"
+)
+
+// Corpus is a content of the corpus file.
+type Corpus struct {
+	Topics   []Topic  `json:"topics" yaml:"topics"`
+	Entities []Entity `json:"entities,omitempty" yaml:"entities,omitempty"`
+}
+
+// Entity is a named list of canonical values in the corpus file.
+type Entity struct {
+	Entity string        `json:"entity" yaml:"entity"`
+	Values []EntityValue `json:"values" yaml:"values"`
+}
+
+// EntityValue is a canonical value of an entity with its synonyms.
+type EntityValue struct {
+	Value    string   `json:"value" yaml:"value"`
+	Synonyms []string `json:"synonyms,omitempty" yaml:"synonyms,omitempty,flow"`
+}
+
+// Topic is all content of a single topic in the corpus file.
+type Topic struct {
+	Topic types.Topic `json:"topic" yaml:"topic"`
+	// Negation applies to all templates of the topic which do not set their own one.
+	Negation      types.Negation `json:"negation,omitempty" yaml:"negation,omitempty"`
+	Templates     []Template     `json:"templates" yaml:"templates"`
+	Answers       []string       `json:"answers" yaml:"answers"`
+	SingleInserts []string       `json:"singleInserts" yaml:"singleInserts"`
+	GroupInserts  []string       `json:"groupInserts" yaml:"groupInserts"`
+	Examples      []string       `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// Template is a template in the corpus file, written either as a plain string
+// or, when it has options, as an object:
+//
+//	templates:
+//	  - "привіт"
+//	  - {template: "так", mode: whole}
+//	  - {template: "добрий ранок", priority: 10}
+//	  - {template: "ні", exact: true}
+//	  - {template: "порадь фільми", negation: suppress}
+type Template struct {
+	Template string          `json:"template" yaml:"template"`
+	Mode     types.MatchMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Priority int             `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Exact    bool            `json:"exact,omitempty" yaml:"exact,omitempty"`
+	Negation types.Negation  `json:"negation,omitempty" yaml:"negation,omitempty"`
+}
+
+// templateObject has the same fields as Template without its custom decoding.
+type templateObject Template
+
+// plain reports whether the template has no options and could be written as a string.
+func (template Template) plain() bool {
+	return template.Mode.OrDefault() == types.MatchContains && template.Priority == 0 && !template.Exact && template.Negation == ""
+}
+
+// UnmarshalYAML decodes template from a string or an object.
+func (template *Template) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&template.Template)
+	}
+
+	var object templateObject
+	if err := value.Decode(&object); err != nil {
+		return err
+	}
+	*template = Template(object)
+
+	return template.validate()
+}
+
+// MarshalYAML encodes template as a string if it has no options.
+func (template Template) MarshalYAML() (any, error) {
+	if template.plain() {
+		return template.Template, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(templateObject(template)); err != nil {
+		return nil, err
+	}
+	node.Style = yaml.FlowStyle
+
+	return node, nil
+}
+
+// UnmarshalJSON decodes template from a string or an object.
+func (template *Template) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &template.Template)
+	}
+
+	var object templateObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*template = Template(object)
+
+	return template.validate()
+}
+
+// MarshalJSON encodes template as a string if it has no options.
+func (template Template) MarshalJSON() ([]byte, error) {
+	if template.plain() {
+		return json.Marshal(template.Template)
+	}
+
+	return json.Marshal(templateObject(template))
+}
+
+// validate checks template options.
+func (template Template) validate() error {
+	if !template.Mode.Valid() {
+		return fmt.Errorf("template %q: unknown mode %q", template.Template, template.Mode)
+	}
+
+	return nil
+}
+
+// toContent converts the corpus topic into the dialogue content.
+func (topic Topic) toContent() types.Content {
+	content := types.Content{
+		Topic:         topic.Topic,
+		Templates:     make([]types.Template, 0, len(topic.Templates)),
+		Answers:       topic.Answers,
+		SingleInserts: topic.SingleInserts,
+		GroupInserts:  topic.GroupInserts,
+		Examples:      topic.Examples,
+	}
+	for _, template := range topic.Templates {
+		negation := template.Negation
+		if negation == "" {
+			negation = topic.Negation
+		}
+
+		content.Templates = append(content.Templates, types.Template{
+			Template: template.Template,
+			Mode:     template.Mode,
+			Priority: template.Priority,
+			Exact:    template.Exact,
+			Negation: negation,
+		})
+	}
+
+	return content
+}
+
+// fromContent converts the dialogue content into the corpus topic.
+func fromContent(content types.Content) Topic {
+	topic := Topic{
+		Topic:         content.Topic,
+		Templates:     make([]Template, 0, len(content.Templates)),
+		Answers:       nonNil(content.Answers),
+		SingleInserts: nonNil(content.SingleInserts),
+		GroupInserts:  nonNil(content.GroupInserts),
+		Examples:      content.Examples,
+	}
+	// negation shared by all templates is written once for the topic.
+	topic.Negation = sharedNegation(content.Templates)
+	for _, template := range content.Templates {
+		mode := template.Mode
+		if mode == types.MatchContains {
+			mode = ""
+		}
+		negation := template.Negation
+		if negation == topic.Negation {
+			negation = ""
+		}
+
+		topic.Templates = append(topic.Templates, Template{Template: template.Template, Mode: mode, Priority: template.Priority, Exact: template.Exact, Negation: negation})
+	}
+
+	return topic
+}
+
+// sharedNegation returns the negation of templates if all of them have the same one.
+func sharedNegation(templates []types.Template) types.Negation {
+	if len(templates) == 0 {
+		return ""
+	}
+
+	for _, template := range templates[1:] {
+		if template.Negation != templates[0].Negation {
+			return ""
+		}
+	}
+
+	return templates[0].Negation
+}
+
+// nonNil returns empty list instead of nil, so that it is written as [] instead of null.
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+
+	return list
+}
+
+// toEntities converts the corpus entity into synonyms of its values, each value being a synonym of itself.
+func (entity Entity) toEntities() ([]types.Entity, error) {
+	if entity.Entity == "" {
+		return nil, ErrNoEntity
+	}
+
+	var entities []types.Entity
+	for _, value := range entity.Values {
+		if value.Value == "" {
+			return nil, fmt.Errorf("%q: %w", entity.Entity, ErrNoEntity)
+		}
+
+		entities = append(entities, types.Entity{Entity: entity.Entity, Value: value.Value, Synonym: value.Value})
+		for _, synonym := range value.Synonyms {
+			entities = append(entities, types.Entity{Entity: entity.Entity, Value: value.Value, Synonym: synonym})
+		}
+	}
+
+	return entities, nil
+}
+
+// fromEntities groups entity synonyms into corpus entities by name and value, keeping their order.
+func fromEntities(entities []types.Entity) []Entity {
+	var list []Entity
+	indexes := make(map[string]int)
+	values := make(map[[2]string]int)
+	for _, entity := range entities {
+		i, ok := indexes[entity.Entity]
+		if !ok {
+			i = len(list)
+			indexes[entity.Entity] = i
+			list = append(list, Entity{Entity: entity.Entity})
+		}
+
+		j, ok := values[[2]string{entity.Entity, entity.Value}]
+		if !ok {
+			j = len(list[i].Values)
+			values[[2]string{entity.Entity, entity.Value}] = j
+			list[i].Values = append(list[i].Values, EntityValue{Value: entity.Value})
+		}
+
+		if entity.Synonym != entity.Value {
+			list[i].Values[j].Synonyms = append(list[i].Values[j].Synonyms, entity.Synonym)
+		}
+	}
+
+	return list
+}
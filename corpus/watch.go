@@ -0,0 +1,76 @@
+package corpus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/zeebo/errs"
+)
+
+// settleDelay is how long a file should stay unchanged before onChange is called,
+// so that an editor writing a file in several steps causes a single call.
+const settleDelay = 300 * time.Millisecond
+
+// Watch calls onChange after the corpus file, or any corpus file of the directory, is changed,
+// until ctx is done.
+func Watch(ctx context.Context, path string, onChange func()) (err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, Error.Wrap(watcher.Close()))
+	}()
+
+	path = filepath.Clean(path)
+	dir, isDir := path, true
+	if !isDirectory(path) {
+		// editors often replace the file instead of writing it, so its directory is watched.
+		dir, isDir = filepath.Dir(path), false
+	}
+
+	err = watcher.Add(dir)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	timer := time.NewTimer(settleDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			return Error.Wrap(err)
+		case event := <-watcher.Events:
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			if isDir && !isCorpusFile(event.Name) || !isDir && filepath.Clean(event.Name) != path {
+				continue
+			}
+
+			// the timer may have fired without its value being received, which would call onChange too early.
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(settleDelay)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+// isDirectory reports whether the path is an existing directory.
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -10,11 +11,14 @@ import (
 	"phatic_dialogue/types"
 )
 
+// ErrNoAnswer indicates that answer does not exist.
+var ErrNoAnswer = errors.New("answer does not exist")
+
 // Answers provides access to answers db.
 //
 // architecture: Database
 type Answers struct {
-	conn *sql.DB
+	conn *connection
 }
 
 // Create creates general answers in the Database.
@@ -27,19 +31,37 @@ func (collectionsDB *Answers) Create(ctx context.Context, answer types.Answer) e
 	return Error.Wrap(err)
 }
 
+// Get returns answer by id from the Database.
+func (collectionsDB *Answers) Get(ctx context.Context, id int) (types.Answer, error) {
+	var answer types.Answer
+
+	query := `SELECT id, answer, topic
+ 	          FROM answers
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&answer.ID, &answer.Answer, &answer.Topic)
+	if errors.Is(err, sql.ErrNoRows) {
+		return answer, ErrNoAnswer
+	}
+
+	return answer, Error.Wrap(err)
+}
+
 // List returns all general answers or by topic from the Database.
 func (collectionsDB *Answers) List(ctx context.Context, topic types.Topic) (_ []types.Answer, err error) {
 	var list []types.Answer
 	var args = make([]any, 0, 1)
 
-	query := `SELECT answer, topic
+	query := `SELECT id, answer, topic
  	          FROM answers
  	          `
 
 	if len(topic) != 0 {
-		query += `WHERE topic = $1`
+		query += `WHERE topic = $1
+ 	          `
 		args = append(args, topic)
 	}
+	query += `ORDER BY id ASC`
 
 	rows, err := collectionsDB.conn.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -51,7 +73,7 @@ func (collectionsDB *Answers) List(ctx context.Context, topic types.Topic) (_ []
 
 	for rows.Next() {
 		var answer types.Answer
-		err := rows.Scan(&answer.Answer, &answer.Topic)
+		err := rows.Scan(&answer.ID, &answer.Answer, &answer.Topic)
 		if err != nil {
 			return list, Error.Wrap(err)
 		}
@@ -64,3 +86,31 @@ func (collectionsDB *Answers) List(ctx context.Context, topic types.Topic) (_ []
 
 	return list, nil
 }
+
+// Update updates answer and its topic by id in the Database.
+func (collectionsDB *Answers) Update(ctx context.Context, answer types.Answer) error {
+	answer.Answer = strings.ToLower(answer.Answer)
+	query := `UPDATE answers
+ 	          SET answer = $1, topic = $2
+ 	          WHERE id = $3`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, answer.Answer, answer.Topic, answer.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoAnswer)
+}
+
+// Delete deletes answer by id from the Database.
+func (collectionsDB *Answers) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM answers
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoAnswer)
+}
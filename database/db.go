@@ -11,68 +11,93 @@ import (
 
 var (
 	// Error is the default db error class.
-	Error      = errs.Class("db error")
-	ErrNoTopic = errors.New("topic does not exist")
+	Error            = errs.Class("db error")
+	ErrNoTopic       = errors.New("topic does not exist")
+	ErrUnknownScheme = errors.New("unknown database url scheme")
 )
 
 // Database provides access to Database tables.
 //
 // architecture: Master Database
 type Database struct {
-	conn *sql.DB
+	sqlDB          *sql.DB
+	conn           *connection
+	dataSourceName string
 
 	templates     *Templates
 	answers       *Answers
 	singleInserts *SingleInserts
 	groupInserts  *GroupInserts
 	topics        *Topics
+	entities      *Entities
+	examples      *Examples
+}
+
+// Config is the database connection configuration.
+type Config struct {
+	// URL is the database url, the backend is picked by its scheme: postgres:// or sqlite://.
+	URL string
+	// MaxOpenConns limits open connections, 0 means no limit.
+	MaxOpenConns int
+	// MaxIdleConns limits idle connections kept in the pool.
+	MaxIdleConns int
 }
 
 // New is a constructor for Database.
-func New(databaseURL string) (*Database, error) {
-	conn, err := sql.Open("postgres", databaseURL)
+func New(config Config) (*Database, error) {
+	sqlDialect, dataSourceName, err := parseURL(config.URL)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
 
-	db := Database{conn: conn}
+	conn, err := sql.Open(sqlDialect.driver, dataSourceName)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	conn.SetMaxOpenConns(config.MaxOpenConns)
+	conn.SetMaxIdleConns(config.MaxIdleConns)
+	if sqlDialect.driver == sqlite.driver {
+		// sqlite allows only one writer at a time.
+		conn.SetMaxOpenConns(1)
+	}
+
+	db := Database{
+		sqlDB:          conn,
+		conn:           &connection{queryer: conn, dialect: sqlDialect},
+		dataSourceName: dataSourceName,
+	}
 	return &db, nil
 }
 
-// CreateSchema creates schema for all tables and databases.
+// CreateSchema creates schema for all tables and databases by applying all pending migrations.
 func (db *Database) CreateSchema(ctx context.Context) (err error) {
-	createTableQuery := `
-        CREATE TABLE IF NOT EXISTS topics (
-            topic   VARCHAR   PRIMARY KEY   NOT NULL
-        );
-        CREATE TABLE IF NOT EXISTS single_inserts (
-            id      SERIAL    PRIMARY KEY                NOT NULL,
-            word    VARCHAR                              NOT NULL,
-            topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
-        );
-        CREATE TABLE IF NOT EXISTS group_inserts (
-            id      SERIAL    PRIMARY KEY                NOT NULL,
-            words   VARCHAR                              NOT NULL,
-            topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
-        );
- 		CREATE TABLE IF NOT EXISTS templates (
- 		    id          SERIAL    PRIMARY KEY                NOT NULL,
-            template    VARCHAR                              NOT NULL,
-            topic       VARCHAR   REFERENCES topics(topic)   NOT NULL
-        );
-		CREATE TABLE IF NOT EXISTS answers (
-		    id         SERIAL    PRIMARY KEY                NOT NULL,
-            answer     VARCHAR                              NOT NULL,
-		    topic      VARCHAR   REFERENCES topics(topic)   NOT NULL
-        );
-       `
-
-	_, err = db.conn.ExecContext(ctx, createTableQuery)
-	if err != nil {
-		return Error.Wrap(err)
+	_, err = db.MigrateUp(ctx)
+
+	return err
+}
+
+// WithTx runs fn with the Database bound to a single transaction,
+// which is committed if fn succeeds and rolled back otherwise.
+func (db *Database) WithTx(ctx context.Context, fn func(tx *Database) error) error {
+	return db.withTx(ctx, nil, fn)
+}
+
+// withReadTx runs fn with the Database bound to a single read only transaction,
+// which sees all tables as they were when it started.
+func (db *Database) withReadTx(ctx context.Context, fn func(tx *Database) error) error {
+	return db.withTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+// withTx runs fn with the Database bound to a single transaction with the options.
+func (db *Database) withTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Database) error) error {
+	err := db.inTx(ctx, opts, func(conn *connection) error {
+		return fn(&Database{sqlDB: db.sqlDB, conn: conn, dataSourceName: db.dataSourceName})
+	})
+	if Error.Has(err) {
+		return err
 	}
 
-	return nil
+	return Error.Wrap(err)
 }
 
 // Answers returns connection to answers db.
@@ -120,7 +145,38 @@ func (db *Database) Topics() *Topics {
 	return db.topics
 }
 
+// Entities returns connection to entities db.
+func (db *Database) Entities() *Entities {
+	if db.entities == nil {
+		db.entities = &Entities{conn: db.conn}
+	}
+
+	return db.entities
+}
+
 // Close closes underlying db connection.
 func (db *Database) Close() error {
-	return Error.Wrap(db.conn.Close())
+	return Error.Wrap(db.sqlDB.Close())
+}
+
+// checkAffected returns notFound error when query has not affected any row.
+func checkAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// Examples returns connection to examples db.
+func (db *Database) Examples() *Examples {
+	if db.examples == nil {
+		db.examples = &Examples{conn: db.conn}
+	}
+
+	return db.examples
 }
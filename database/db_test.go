@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestOpenSQLite(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.sqlDB.PingContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var foreignKeys bool
+	if err := db.conn.QueryRowContext(context.Background(), `PRAGMA foreign_keys`).Scan(&foreignKeys); err != nil {
+		t.Fatal(err)
+	}
+	if !foreignKeys {
+		t.Error("foreign keys are not enabled")
+	}
+}
+
+func TestMissingRows(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSchema(t)
+	if err := db.Topics().Create(ctx, "привітання"); err != nil {
+		t.Fatal(err)
+	}
+
+	const missing = 1000
+	tests := []struct {
+		name string
+		op   func() error
+		err  error
+	}{
+		{name: "update topic", op: func() error { return db.Topics().Update(ctx, "прощання", "бувай") }, err: ErrNoTopic},
+		{name: "delete topic", op: func() error { return db.Topics().Delete(ctx, "прощання") }, err: ErrNoTopic},
+		{name: "update template", op: func() error {
+			return db.Templates().Update(ctx, types.Template{ID: missing, Template: "привіт", Topic: "привітання"})
+		}, err: ErrNoTemplate},
+		{name: "delete template", op: func() error { return db.Templates().Delete(ctx, missing) }, err: ErrNoTemplate},
+		{name: "update answer", op: func() error {
+			return db.Answers().Update(ctx, types.Answer{ID: missing, Answer: "привіт", Topic: "привітання"})
+		}, err: ErrNoAnswer},
+		{name: "delete answer", op: func() error { return db.Answers().Delete(ctx, missing) }, err: ErrNoAnswer},
+		{name: "update single insert", op: func() error {
+			return db.SingleInserts().Update(ctx, types.SingleInsert{ID: missing, Word: "друже", Topic: "привітання"})
+		}, err: ErrNoSingleInsert},
+		{name: "delete single insert", op: func() error { return db.SingleInserts().Delete(ctx, missing) }, err: ErrNoSingleInsert},
+		{name: "update group insert", op: func() error {
+			return db.GroupInserts().Update(ctx, types.GroupInsert{ID: missing, Words: "як справи", Topic: "привітання"})
+		}, err: ErrNoGroupInsert},
+		{name: "delete group insert", op: func() error { return db.GroupInserts().Delete(ctx, missing) }, err: ErrNoGroupInsert},
+		{name: "update entity", op: func() error {
+			return db.Entities().Update(ctx, types.Entity{ID: missing, Entity: "city", Value: "київ", Synonym: "києві"})
+		}, err: ErrNoEntity},
+		{name: "delete entity", op: func() error { return db.Entities().Delete(ctx, missing) }, err: ErrNoEntity},
+		{name: "update example", op: func() error {
+			return db.Examples().Update(ctx, types.Example{ID: missing, Example: "добрий вечір", Topic: "привітання"})
+		}, err: ErrNoExample},
+		{name: "delete example", op: func() error { return db.Examples().Delete(ctx, missing) }, err: ErrNoExample},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.op(); !errors.Is(err, test.err) {
+				t.Errorf("error = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // using sqlite driver.
+)
+
+// dialect describes differences between supported sql databases.
+type dialect struct {
+	// driver is a name of the registered sql driver.
+	driver string
+	// migrations is a directory of the dialect migrations.
+	migrations string
+	// rebind rewrites query placeholders into the dialect ones.
+	rebind func(query string) string
+}
+
+// postgres is the dialect of the PostgreSQL database.
+var postgres = dialect{
+	driver:     "postgres",
+	migrations: "migrations/postgres",
+	rebind:     func(query string) string { return query },
+}
+
+// sqlite is the dialect of the SQLite database.
+var sqlite = dialect{
+	driver:     "sqlite3",
+	migrations: "migrations/sqlite",
+	rebind: func(query string) string {
+		// $1 is a named parameter in sqlite, while ?1 is the numbered one.
+		return placeholder.ReplaceAllStringFunc(query, func(match string) string {
+			if !strings.HasPrefix(match, "$") {
+				return match
+			}
+
+			return "?" + match[1:]
+		})
+	},
+}
+
+// placeholder matches postgres numbered placeholders, together with quoted literals and identifiers,
+// so that placeholders inside of them are left as they are.
+var placeholder = regexp.MustCompile(`'(?:[^']|'')*'|"(?:[^"]|"")*"|\$\d+`)
+
+// parseURL returns dialect and driver data source name by the database url scheme.
+//
+// Supported schemes are postgres://, postgresql://, sqlite:// and sqlite3://.
+func parseURL(databaseURL string) (dialect, string, error) {
+	switch {
+	case strings.HasPrefix(databaseURL, "postgres://"), strings.HasPrefix(databaseURL, "postgresql://"):
+		return postgres, databaseURL, nil
+	case strings.HasPrefix(databaseURL, "sqlite://"):
+		return sqlite, sqliteDSN(strings.TrimPrefix(databaseURL, "sqlite://")), nil
+	case strings.HasPrefix(databaseURL, "sqlite3://"):
+		return sqlite, sqliteDSN(strings.TrimPrefix(databaseURL, "sqlite3://")), nil
+	default:
+		return dialect{}, "", ErrUnknownScheme
+	}
+}
+
+// sqliteDSN makes sqlite data source name from the file path, enabling foreign keys.
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	return "file:" + path + separator + "_foreign_keys=on"
+}
+
+// queryer is implemented by both database connection and transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// connection is a database connection or transaction which adapts queries to the dialect.
+type connection struct {
+	queryer queryer
+	dialect dialect
+}
+
+// ExecContext executes a query without returning any rows.
+func (conn *connection) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return conn.queryer.ExecContext(ctx, conn.dialect.rebind(query), args...)
+}
+
+// QueryContext executes a query that returns rows.
+func (conn *connection) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return conn.queryer.QueryContext(ctx, conn.dialect.rebind(query), args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row.
+func (conn *connection) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return conn.queryer.QueryRowContext(ctx, conn.dialect.rebind(query), args...)
+}
@@ -0,0 +1,43 @@
+package database
+
+import "testing"
+
+func TestSQLiteRebind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: `SELECT 1`, want: `SELECT 1`},
+		{query: `SELECT id FROM topics WHERE topic = $1`, want: `SELECT id FROM topics WHERE topic = ?1`},
+		{query: `VALUES ($1, $2, $10)`, want: `VALUES (?1, ?2, ?10)`},
+		{query: `SELECT '$1', $1`, want: `SELECT '$1', ?1`},
+		{query: `SELECT 'привіт $' || $2`, want: `SELECT 'привіт $' || ?2`},
+		{query: `SELECT 'it''s $1', $1`, want: `SELECT 'it''s $1', ?1`},
+		{query: `SELECT "$1" FROM t WHERE a = $1`, want: `SELECT "$1" FROM t WHERE a = ?1`},
+	}
+	for _, test := range tests {
+		if rebound := sqlite.rebind(test.query); rebound != test.want {
+			t.Errorf("rebind(%q) = %q, want %q", test.query, rebound, test.want)
+		}
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		driver string
+		dsn    string
+		err    error
+	}{
+		{url: "postgres://postgres@localhost/db", driver: "postgres", dsn: "postgres://postgres@localhost/db"},
+		{url: "sqlite://dialogue.db", driver: "sqlite3", dsn: "file:dialogue.db?_foreign_keys=on"},
+		{url: "sqlite3://dialogue.db?cache=shared", driver: "sqlite3", dsn: "file:dialogue.db?cache=shared&_foreign_keys=on"},
+		{url: "mysql://localhost/db", err: ErrUnknownScheme},
+	}
+	for _, test := range tests {
+		sqlDialect, dsn, err := parseURL(test.url)
+		if err != test.err || sqlDialect.driver != test.driver || dsn != test.dsn {
+			t.Errorf("parseURL(%q) = %q, %q, %v, want %q, %q, %v", test.url, sqlDialect.driver, dsn, err, test.driver, test.dsn, test.err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"phatic_dialogue/types"
+)
+
+// ErrNoEntity indicates that entity does not exist.
+var ErrNoEntity = errors.New("entity does not exist")
+
+// Entities provides access to entities db.
+//
+// architecture: Database
+type Entities struct {
+	conn *connection
+}
+
+// Create creates entity synonym in the Database.
+func (collectionsDB *Entities) Create(ctx context.Context, entity types.Entity) error {
+	entity = lowerEntity(entity)
+	query := `INSERT INTO entities(entity, value, synonym) VALUES ($1, $2, $3)`
+
+	_, err := collectionsDB.conn.ExecContext(ctx, query, entity.Entity, entity.Value, entity.Synonym)
+
+	return Error.Wrap(err)
+}
+
+// Get returns entity synonym by id from the Database.
+func (collectionsDB *Entities) Get(ctx context.Context, id int) (types.Entity, error) {
+	var entity types.Entity
+
+	query := `SELECT id, entity, value, synonym
+ 	          FROM entities
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&entity.ID, &entity.Entity, &entity.Value, &entity.Synonym)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity, ErrNoEntity
+	}
+
+	return entity, Error.Wrap(err)
+}
+
+// List returns synonyms of all entities or of a single entity from the Database.
+func (collectionsDB *Entities) List(ctx context.Context, name string) (_ []types.Entity, err error) {
+	var list []types.Entity
+	var args = make([]any, 0, 1)
+
+	query := `SELECT id, entity, value, synonym
+ 	          FROM entities
+ 	          `
+
+	if len(name) != 0 {
+		query += `WHERE entity = $1
+ 	          `
+		args = append(args, name)
+	}
+	query += `ORDER BY entity ASC, id ASC`
+
+	rows, err := collectionsDB.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return list, Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var entity types.Entity
+		err := rows.Scan(&entity.ID, &entity.Entity, &entity.Value, &entity.Synonym)
+		if err != nil {
+			return list, Error.Wrap(err)
+		}
+
+		list = append(list, entity)
+	}
+	if err = rows.Err(); err != nil {
+		return list, Error.Wrap(err)
+	}
+
+	return list, nil
+}
+
+// Update updates entity synonym and its value by id in the Database.
+func (collectionsDB *Entities) Update(ctx context.Context, entity types.Entity) error {
+	entity = lowerEntity(entity)
+	query := `UPDATE entities
+ 	          SET entity = $1, value = $2, synonym = $3
+ 	          WHERE id = $4`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, entity.Entity, entity.Value, entity.Synonym, entity.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoEntity)
+}
+
+// Delete deletes entity synonym by id from the Database.
+func (collectionsDB *Entities) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM entities
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoEntity)
+}
+
+// lowerEntity lowers entity name, value and synonym the same way as the rest of the content.
+func lowerEntity(entity types.Entity) types.Entity {
+	entity.Entity = strings.ToLower(entity.Entity)
+	entity.Value = strings.ToLower(entity.Value)
+	entity.Synonym = strings.ToLower(entity.Synonym)
+
+	return entity
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"phatic_dialogue/types"
+)
+
+// ErrNoExample indicates that example does not exist.
+var ErrNoExample = errors.New("example does not exist")
+
+// Examples provides access to examples db.
+//
+// architecture: Database
+type Examples struct {
+	conn *connection
+}
+
+// Create creates example in the Database.
+func (collectionsDB *Examples) Create(ctx context.Context, example types.Example) error {
+	example.Example = strings.ToLower(example.Example)
+	query := `INSERT INTO examples(example, topic) VALUES ($1, $2)`
+
+	_, err := collectionsDB.conn.ExecContext(ctx, query, example.Example, example.Topic)
+
+	return Error.Wrap(err)
+}
+
+// Get returns example by id from the Database.
+func (collectionsDB *Examples) Get(ctx context.Context, id int) (types.Example, error) {
+	var example types.Example
+
+	query := `SELECT id, example, topic
+ 	          FROM examples
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&example.ID, &example.Example, &example.Topic)
+	if errors.Is(err, sql.ErrNoRows) {
+		return example, ErrNoExample
+	}
+
+	return example, Error.Wrap(err)
+}
+
+// List returns all examples or by topic from the Database.
+func (collectionsDB *Examples) List(ctx context.Context, topic types.Topic) (_ []types.Example, err error) {
+	var list []types.Example
+	var args = make([]any, 0, 1)
+
+	query := `SELECT id, example, topic
+ 	          FROM examples
+ 	          `
+
+	if len(topic) != 0 {
+		query += `WHERE topic = $1
+ 	          `
+		args = append(args, topic)
+	}
+	query += `ORDER BY id ASC`
+
+	rows, err := collectionsDB.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return list, Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var example types.Example
+		err := rows.Scan(&example.ID, &example.Example, &example.Topic)
+		if err != nil {
+			return list, Error.Wrap(err)
+		}
+
+		list = append(list, example)
+	}
+	if err = rows.Err(); err != nil {
+		return list, Error.Wrap(err)
+	}
+
+	return list, nil
+}
+
+// Update updates example and its topic by id in the Database.
+func (collectionsDB *Examples) Update(ctx context.Context, example types.Example) error {
+	example.Example = strings.ToLower(example.Example)
+	query := `UPDATE examples
+ 	          SET example = $1, topic = $2
+ 	          WHERE id = $3`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, example.Example, example.Topic, example.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoExample)
+}
+
+// Delete deletes example by id from the Database.
+func (collectionsDB *Examples) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM examples
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoExample)
+}
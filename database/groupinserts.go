@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -10,11 +11,14 @@ import (
 	"phatic_dialogue/types"
 )
 
+// ErrNoGroupInsert indicates that group insert does not exist.
+var ErrNoGroupInsert = errors.New("group insert does not exist")
+
 // GroupInserts provides access to group_inserts db.
 //
 // architecture: Database
 type GroupInserts struct {
-	conn *sql.DB
+	conn *connection
 }
 
 // Create creates groupInsert in the Database.
@@ -27,19 +31,37 @@ func (collectionsDB *GroupInserts) Create(ctx context.Context, groupInsert types
 	return Error.Wrap(err)
 }
 
+// Get returns group insert by id from the Database.
+func (collectionsDB *GroupInserts) Get(ctx context.Context, id int) (types.GroupInsert, error) {
+	var groupInsert types.GroupInsert
+
+	query := `SELECT id, words, topic
+ 	          FROM group_inserts
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&groupInsert.ID, &groupInsert.Words, &groupInsert.Topic)
+	if errors.Is(err, sql.ErrNoRows) {
+		return groupInsert, ErrNoGroupInsert
+	}
+
+	return groupInsert, Error.Wrap(err)
+}
+
 // List returns all group inserts or by topic from the Database.
 func (collectionsDB *GroupInserts) List(ctx context.Context, topic types.Topic) (_ []types.GroupInsert, err error) {
 	var list []types.GroupInsert
 	var args = make([]any, 0, 1)
 
-	query := `SELECT words, topic
+	query := `SELECT id, words, topic
  	          FROM group_inserts
  	          `
 
 	if len(topic) != 0 {
-		query += `WHERE topic = $1`
+		query += `WHERE topic = $1
+ 	          `
 		args = append(args, topic)
 	}
+	query += `ORDER BY id ASC`
 
 	rows, err := collectionsDB.conn.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -51,7 +73,7 @@ func (collectionsDB *GroupInserts) List(ctx context.Context, topic types.Topic)
 
 	for rows.Next() {
 		var groupInsert types.GroupInsert
-		err := rows.Scan(&groupInsert.Words, &groupInsert.Topic)
+		err := rows.Scan(&groupInsert.ID, &groupInsert.Words, &groupInsert.Topic)
 		if err != nil {
 			return list, Error.Wrap(err)
 		}
@@ -64,3 +86,31 @@ func (collectionsDB *GroupInserts) List(ctx context.Context, topic types.Topic)
 
 	return list, nil
 }
+
+// Update updates group insert and its topic by id in the Database.
+func (collectionsDB *GroupInserts) Update(ctx context.Context, groupInsert types.GroupInsert) error {
+	groupInsert.Words = strings.ToLower(groupInsert.Words)
+	query := `UPDATE group_inserts
+ 	          SET words = $1, topic = $2
+ 	          WHERE id = $3`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, groupInsert.Words, groupInsert.Topic, groupInsert.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoGroupInsert)
+}
+
+// Delete deletes group insert by id from the Database.
+func (collectionsDB *GroupInserts) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM group_inserts
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoGroupInsert)
+}
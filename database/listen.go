@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/zeebo/errs"
+)
+
+const (
+	// contentChannel is the postgres notification channel of content changes.
+	contentChannel = "content_changed"
+	// pollInterval is how often sqlite content version is checked.
+	pollInterval = time.Second
+	// settleDelay is how long changes should stop coming before onChange is called,
+	// so that a burst of changes, like a seed, causes a single call.
+	settleDelay = 300 * time.Millisecond
+)
+
+// Listen calls onChange after content tables are changed, until ctx is done.
+//
+// postgres delivers changes with LISTEN/NOTIFY once the changing transaction is committed,
+// while sqlite has no notifications and its content version is polled instead.
+func (db *Database) Listen(ctx context.Context, onChange func()) error {
+	changes := make(chan struct{}, 1)
+	go settle(ctx, changes, onChange)
+
+	if db.conn.dialect.driver == sqlite.driver {
+		return db.poll(ctx, changes)
+	}
+
+	return db.listen(ctx, changes)
+}
+
+// listen receives postgres notifications of content changes.
+func (db *Database) listen(ctx context.Context, changes chan<- struct{}) (err error) {
+	listener := pq.NewListener(db.dataSourceName, time.Second, time.Minute, nil)
+	defer func() {
+		err = errs.Combine(err, Error.Wrap(listener.Close()))
+	}()
+
+	err = listener.Listen(contentChannel)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-listener.Notify:
+			// nil notification means reconnect, when changes could be missed, so it is a change too.
+			signal(changes)
+		case <-ping.C:
+			go func() { _ = listener.Ping() }()
+		}
+	}
+}
+
+// poll checks sqlite content version for changes.
+func (db *Database) poll(ctx context.Context, changes chan<- struct{}) error {
+	version, err := db.contentVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := db.contentVersion(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if current != version {
+			version = current
+			signal(changes)
+		}
+	}
+}
+
+// contentVersion returns sqlite version of the content, which grows on every change.
+func (db *Database) contentVersion(ctx context.Context) (int64, error) {
+	var version int64
+
+	query := `SELECT version
+ 	          FROM content_version`
+
+	err := db.conn.QueryRowContext(ctx, query).Scan(&version)
+
+	return version, Error.Wrap(err)
+}
+
+// signal sends a change without blocking, since one pending change is enough.
+func signal(changes chan<- struct{}) {
+	select {
+	case changes <- struct{}{}:
+	default:
+	}
+}
+
+// settle calls onChange once changes stop coming for settleDelay.
+func settle(ctx context.Context, changes <-chan struct{}, onChange func()) {
+	timer := time.NewTimer(settleDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-changes:
+			// the timer may have fired without its value being received, which would call onChange too early.
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(settleDelay)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
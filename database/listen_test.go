@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSettle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	changes := make(chan struct{}, 1)
+	go settle(ctx, changes, func() { calls.Add(1) })
+
+	// a burst of changes causes a single call once it stops.
+	for i := 0; i < 5; i++ {
+		signal(changes)
+		time.Sleep(settleDelay / 5)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("calls during the burst = %d, want 0", n)
+	}
+
+	time.Sleep(2 * settleDelay)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("calls after the burst = %d, want 1", n)
+	}
+
+	// a change after the call causes another one.
+	signal(changes)
+	time.Sleep(2 * settleDelay)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("calls after another change = %d, want 2", n)
+	}
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"phatic_dialogue/types"
+)
+
+// Store keeps dialogue data in memory without any database.
+//
+// architecture: Master Database
+type Store struct {
+	mu sync.RWMutex
+
+	topics        []types.Topic
+	templates     []types.Template
+	answers       []types.Answer
+	singleInserts []types.SingleInsert
+	groupInserts  []types.GroupInsert
+	entities      []types.Entity
+	examples      []types.Example
+
+	// lastID is the latest id given to any stored element.
+	lastID int
+}
+
+// New is a constructor for Store, filled with the corpus.
+func New(corpus types.Corpus) *Store {
+	store := &Store{}
+	for _, content := range corpus.Contents {
+		store.load(content)
+	}
+	for _, entity := range corpus.Entities {
+		store.entities = append(store.entities, normalizeEntity(entity, store.nextID()))
+	}
+
+	return store
+}
+
+// Replace atomically replaces all data of the Store with the corpus.
+func (store *Store) Replace(corpus types.Corpus) {
+	replacement := New(corpus)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.topics = replacement.topics
+	store.templates = replacement.templates
+	store.answers = replacement.answers
+	store.singleInserts = replacement.singleInserts
+	store.groupInserts = replacement.groupInserts
+	store.entities = replacement.entities
+	store.examples = replacement.examples
+	store.lastID = replacement.lastID
+}
+
+// load appends content of a single topic to the Store.
+func (store *Store) load(content types.Content) {
+	topic := normalizeTopic(content.Topic)
+	store.topics = append(store.topics, topic)
+
+	for _, template := range content.Templates {
+		store.templates = append(store.templates, types.Template{
+			ID:       store.nextID(),
+			Template: normalize(template.Template),
+			Topic:    topic,
+			Mode:     template.Mode.OrDefault(),
+			Priority: template.Priority,
+			Exact:    template.Exact,
+			Negation: types.Negation(normalize(string(template.Negation))),
+		})
+	}
+	for _, answer := range content.Answers {
+		store.answers = append(store.answers, types.Answer{ID: store.nextID(), Answer: normalize(answer), Topic: topic})
+	}
+	for _, word := range content.SingleInserts {
+		store.singleInserts = append(store.singleInserts, types.SingleInsert{ID: store.nextID(), Word: normalize(word), Topic: topic})
+	}
+	for _, words := range content.GroupInserts {
+		store.groupInserts = append(store.groupInserts, types.GroupInsert{ID: store.nextID(), Words: normalize(words), Topic: topic})
+	}
+	for _, example := range content.Examples {
+		store.examples = append(store.examples, types.Example{ID: store.nextID(), Example: normalize(example), Topic: topic})
+	}
+}
+
+// Corpus returns all content of the Store grouped by topic together with all entities, read under a single lock.
+func (store *Store) Corpus(ctx context.Context) (types.Corpus, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	contents := make([]types.Content, 0, len(store.topics))
+	indexes := make(map[types.Topic]int, len(store.topics))
+	for _, topic := range store.topics {
+		indexes[topic] = len(contents)
+		contents = append(contents, types.Content{Topic: topic})
+	}
+	content := func(topic types.Topic) *types.Content {
+		index, ok := indexes[topic]
+		if !ok {
+			index = len(contents)
+			indexes[topic] = index
+			contents = append(contents, types.Content{Topic: topic})
+		}
+
+		return &contents[index]
+	}
+
+	for _, template := range store.templates {
+		content(template.Topic).Templates = append(content(template.Topic).Templates, template)
+	}
+	for _, answer := range store.answers {
+		content(answer.Topic).Answers = append(content(answer.Topic).Answers, answer.Answer)
+	}
+	for _, singleInsert := range store.singleInserts {
+		content(singleInsert.Topic).SingleInserts = append(content(singleInsert.Topic).SingleInserts, singleInsert.Word)
+	}
+	for _, groupInsert := range store.groupInserts {
+		content(groupInsert.Topic).GroupInserts = append(content(groupInsert.Topic).GroupInserts, groupInsert.Words)
+	}
+	for _, example := range store.examples {
+		content(example.Topic).Examples = append(content(example.Topic).Examples, example.Example)
+	}
+
+	return types.Corpus{Contents: contents, Entities: append([]types.Entity(nil), store.entities...)}, nil
+}
+
+// nextID returns a new unique id for the stored element.
+func (store *Store) nextID() int {
+	store.lastID++
+	return store.lastID
+}
+
+// normalize lowers the text the same way the database repositories do.
+func normalize(text string) string {
+	return strings.ToLower(text)
+}
+
+// normalizeEntity lowers the entity the same way the database repositories do and sets its id.
+func normalizeEntity(entity types.Entity, id int) types.Entity {
+	entity.ID = id
+	entity.Entity = normalize(entity.Entity)
+	entity.Value = normalize(entity.Value)
+	entity.Synonym = normalize(entity.Synonym)
+
+	return entity
+}
+
+// normalizeTopic lowers the topic the same way the database repositories do.
+func normalizeTopic(topic types.Topic) types.Topic {
+	return types.Topic(normalize(string(topic)))
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestStoreCorpus(t *testing.T) {
+	store := New(types.Corpus{
+		Contents: []types.Content{
+			{
+				Topic:         "Привітання",
+				Templates:     []types.Template{{Template: "Привіт"}, {Template: "добрий день", Mode: types.MatchWhole, Negation: "Прощання"}},
+				Answers:       []string{"Привіт $"},
+				SingleInserts: []string{"друже"},
+				GroupInserts:  []string{", як справи?"},
+				Examples:      []string{"доброго ранку"},
+			},
+			{Topic: "прощання", Answers: []string{"бувай"}},
+		},
+		Entities: []types.Entity{{Entity: "City", Value: "Київ", Synonym: "Києві"}},
+	})
+
+	corpus, err := store.Corpus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := types.Corpus{
+		Contents: []types.Content{
+			{
+				Topic: "привітання",
+				Templates: []types.Template{
+					{ID: 1, Template: "привіт", Topic: "привітання", Mode: types.MatchContains},
+					{ID: 2, Template: "добрий день", Topic: "привітання", Mode: types.MatchWhole, Negation: "прощання"},
+				},
+				Answers:       []string{"привіт $"},
+				SingleInserts: []string{"друже"},
+				GroupInserts:  []string{", як справи?"},
+				Examples:      []string{"доброго ранку"},
+			},
+			{Topic: "прощання", Answers: []string{"бувай"}},
+		},
+		Entities: []types.Entity{{ID: 8, Entity: "city", Value: "київ", Synonym: "києві"}},
+	}
+	if !reflect.DeepEqual(corpus, want) {
+		t.Errorf("corpus = %+v, want %+v", corpus, want)
+	}
+}
+
+func TestStoreReplace(t *testing.T) {
+	store := New(types.Corpus{Contents: []types.Content{{Topic: "привітання", Answers: []string{"привіт"}}}})
+	store.Replace(types.Corpus{Contents: []types.Content{{Topic: "прощання", Answers: []string{"бувай"}}}})
+
+	corpus, err := store.Corpus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := types.Corpus{Contents: []types.Content{{Topic: "прощання", Answers: []string{"бувай"}}}}
+	if !reflect.DeepEqual(corpus, want) {
+		t.Errorf("corpus = %+v, want %+v", corpus, want)
+	}
+}
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrNoMigrations indicates that there is no applied migration to revert.
+var ErrNoMigrations = errors.New("no applied migrations")
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migration is a single numbered change of the database schema.
+type migration struct {
+	version     int
+	description string
+	up          string
+	down        string
+}
+
+// Migration describes state of a single migration in the Database.
+type Migration struct {
+	Version     int
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// loadMigrations reads migrations of the dialect ordered by version.
+//
+// Migrations are stored as NNNN_description.up.sql and NNNN_description.down.sql pairs.
+func loadMigrations(sqlDialect dialect) ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, sqlDialect.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction := strings.TrimSuffix(name, ".sql"), ""
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			base, direction = strings.TrimSuffix(base, ".up"), "up"
+		case strings.HasSuffix(base, ".down"):
+			base, direction = strings.TrimSuffix(base, ".down"), "down"
+		default:
+			return nil, fmt.Errorf("unexpected migration file %q", name)
+		}
+
+		number, description, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q", name)
+		}
+
+		query, err := fs.ReadFile(migrationFiles, path.Join(sqlDialect.migrations, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, description: description}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(query)
+		} else {
+			m.down = string(query)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %04d has no up or down part", m.version)
+		}
+
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// createMigrationsTable creates the table which tracks applied migrations.
+func (db *Database) createMigrationsTable(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+                  version       INTEGER     PRIMARY KEY   NOT NULL,
+                  description   VARCHAR                   NOT NULL,
+                  applied_at    TIMESTAMP                 NOT NULL
+              )`
+
+	_, err := db.conn.ExecContext(ctx, query)
+
+	return Error.Wrap(err)
+}
+
+// appliedMigrations returns application time of applied migrations by version.
+func (db *Database) appliedMigrations(ctx context.Context) (_ map[int]time.Time, err error) {
+	applied := make(map[int]time.Time)
+
+	query := `SELECT version, applied_at
+ 	          FROM schema_migrations`
+
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return applied, Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return applied, Error.Wrap(err)
+		}
+
+		applied[version] = appliedAt
+	}
+	if err = rows.Err(); err != nil {
+		return applied, Error.Wrap(err)
+	}
+
+	return applied, nil
+}
+
+// MigrationStatus returns all known migrations and whether they are applied.
+func (db *Database) MigrationStatus(ctx context.Context) ([]Migration, error) {
+	migrations, err := loadMigrations(db.conn.dialect)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = db.createMigrationsTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt, ok := applied[m.version]
+		statuses = append(statuses, Migration{
+			Version:     m.version,
+			Description: m.description,
+			Applied:     ok,
+			AppliedAt:   appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// MigrateUp applies all pending migrations in order and returns applied ones.
+func (db *Database) MigrateUp(ctx context.Context) ([]Migration, error) {
+	migrations, err := loadMigrations(db.conn.dialect)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = db.createMigrationsTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+
+		appliedAt := time.Now().UTC()
+		err = db.inTx(ctx, nil, func(tx *connection) error {
+			if _, err := tx.ExecContext(ctx, m.up); err != nil {
+				return err
+			}
+
+			query := `INSERT INTO schema_migrations(version, description, applied_at) VALUES ($1, $2, $3)`
+			_, err := tx.ExecContext(ctx, query, m.version, m.description, appliedAt)
+
+			return err
+		})
+		if err != nil {
+			return done, Error.New("migration %04d %s: %v", m.version, m.description, err)
+		}
+
+		done = append(done, Migration{Version: m.version, Description: m.description, Applied: true, AppliedAt: appliedAt})
+	}
+
+	return done, nil
+}
+
+// MigrateDown reverts the given number of the latest applied migrations and returns reverted ones.
+func (db *Database) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.conn.dialect)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = db.createMigrationsTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		return nil, Error.Wrap(ErrNoMigrations)
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.version]; !ok {
+			continue
+		}
+
+		err = db.inTx(ctx, nil, func(tx *connection) error {
+			if _, err := tx.ExecContext(ctx, m.down); err != nil {
+				return err
+			}
+
+			query := `DELETE FROM schema_migrations WHERE version = $1`
+			_, err := tx.ExecContext(ctx, query, m.version)
+
+			return err
+		})
+		if err != nil {
+			return done, Error.New("migration %04d %s: %v", m.version, m.description, err)
+		}
+
+		done = append(done, Migration{Version: m.version, Description: m.description})
+	}
+
+	return done, nil
+}
+
+// inTx runs fn inside of a database transaction with the options, which is committed if fn succeeds.
+func (db *Database) inTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *connection) error) (err error) {
+	tx, err := db.sqlDB.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ignoreDone(tx.Rollback()))
+		}
+	}()
+
+	err = fn(&connection{queryer: tx, dialect: db.conn.dialect})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ignoreDone drops the error of rollback of an already finished transaction.
+func ignoreDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS topics (
+    topic   VARCHAR   PRIMARY KEY   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS single_inserts (
+    id      SERIAL    PRIMARY KEY                NOT NULL,
+    word    VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS group_inserts (
+    id      SERIAL    PRIMARY KEY                NOT NULL,
+    words   VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS templates (
+    id          SERIAL    PRIMARY KEY                NOT NULL,
+    template    VARCHAR                              NOT NULL,
+    topic       VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS answers (
+    id         SERIAL    PRIMARY KEY                NOT NULL,
+    answer     VARCHAR                              NOT NULL,
+    topic      VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
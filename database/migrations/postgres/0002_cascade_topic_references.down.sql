@@ -0,0 +1,12 @@
+ALTER TABLE single_inserts
+    DROP CONSTRAINT IF EXISTS single_inserts_topic_fkey,
+    ADD CONSTRAINT single_inserts_topic_fkey FOREIGN KEY (topic) REFERENCES topics(topic);
+ALTER TABLE group_inserts
+    DROP CONSTRAINT IF EXISTS group_inserts_topic_fkey,
+    ADD CONSTRAINT group_inserts_topic_fkey FOREIGN KEY (topic) REFERENCES topics(topic);
+ALTER TABLE templates
+    DROP CONSTRAINT IF EXISTS templates_topic_fkey,
+    ADD CONSTRAINT templates_topic_fkey FOREIGN KEY (topic) REFERENCES topics(topic);
+ALTER TABLE answers
+    DROP CONSTRAINT IF EXISTS answers_topic_fkey,
+    ADD CONSTRAINT answers_topic_fkey FOREIGN KEY (topic) REFERENCES topics(topic);
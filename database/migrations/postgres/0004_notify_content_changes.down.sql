@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS answers_content_changed ON answers;
+DROP TRIGGER IF EXISTS templates_content_changed ON templates;
+DROP TRIGGER IF EXISTS group_inserts_content_changed ON group_inserts;
+DROP TRIGGER IF EXISTS single_inserts_content_changed ON single_inserts;
+DROP TRIGGER IF EXISTS topics_content_changed ON topics;
+DROP FUNCTION IF EXISTS notify_content_changed();
@@ -0,0 +1,17 @@
+CREATE OR REPLACE FUNCTION notify_content_changed() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('content_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER topics_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON topics
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
+CREATE TRIGGER single_inserts_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON single_inserts
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
+CREATE TRIGGER group_inserts_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON group_inserts
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
+CREATE TRIGGER templates_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON templates
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
+CREATE TRIGGER answers_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON answers
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
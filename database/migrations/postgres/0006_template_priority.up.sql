@@ -0,0 +1 @@
+ALTER TABLE templates ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS entities_content_changed ON entities;
+DROP TABLE IF EXISTS entities;
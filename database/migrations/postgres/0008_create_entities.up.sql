@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS entities (
+    id        SERIAL    PRIMARY KEY   NOT NULL,
+    entity    VARCHAR                 NOT NULL,
+    value     VARCHAR                 NOT NULL,
+    synonym   VARCHAR                 NOT NULL,
+    UNIQUE (entity, synonym)
+);
+
+CREATE TRIGGER entities_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON entities
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
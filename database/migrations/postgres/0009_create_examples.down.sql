@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS examples_content_changed ON examples;
+DROP TABLE IF EXISTS examples;
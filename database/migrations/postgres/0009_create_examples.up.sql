@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS examples (
+    id        SERIAL    PRIMARY KEY                                                    NOT NULL,
+    example   VARCHAR                                                                  NOT NULL,
+    topic     VARCHAR   REFERENCES topics(topic) ON UPDATE CASCADE ON DELETE CASCADE   NOT NULL,
+    UNIQUE (topic, example)
+);
+
+CREATE TRIGGER examples_content_changed AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON examples
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_content_changed();
@@ -0,0 +1 @@
+ALTER TABLE templates ADD COLUMN negation VARCHAR NOT NULL DEFAULT '';
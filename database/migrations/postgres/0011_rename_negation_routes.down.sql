@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS topics_rename_negation_routes ON topics;
+DROP FUNCTION IF EXISTS rename_negation_routes();
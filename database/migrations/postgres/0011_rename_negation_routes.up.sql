@@ -0,0 +1,11 @@
+-- templates route negated matches to counterpart topics by name, so renames are followed here.
+CREATE OR REPLACE FUNCTION rename_negation_routes() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE templates SET negation = NEW.topic WHERE negation = OLD.topic;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER topics_rename_negation_routes AFTER UPDATE OF topic ON topics
+    FOR EACH ROW WHEN (OLD.topic <> NEW.topic AND OLD.topic <> 'suppress')
+    EXECUTE FUNCTION rename_negation_routes();
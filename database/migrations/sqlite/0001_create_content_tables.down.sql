@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS answers;
+DROP TABLE IF EXISTS templates;
+DROP TABLE IF EXISTS group_inserts;
+DROP TABLE IF EXISTS single_inserts;
+DROP TABLE IF EXISTS topics;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS topics (
+    topic   VARCHAR   PRIMARY KEY   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS single_inserts (
+    id      INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    word    VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS group_inserts (
+    id      INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    words   VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS templates (
+    id          INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    template    VARCHAR                              NOT NULL,
+    topic       VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+CREATE TABLE IF NOT EXISTS answers (
+    id         INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    answer     VARCHAR                              NOT NULL,
+    topic      VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
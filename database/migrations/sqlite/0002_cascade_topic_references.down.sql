@@ -0,0 +1,35 @@
+CREATE TABLE single_inserts_new (
+    id      INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    word    VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+INSERT INTO single_inserts_new(id, word, topic) SELECT id, word, topic FROM single_inserts;
+DROP TABLE single_inserts;
+ALTER TABLE single_inserts_new RENAME TO single_inserts;
+
+CREATE TABLE group_inserts_new (
+    id      INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    words   VARCHAR                              NOT NULL,
+    topic   VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+INSERT INTO group_inserts_new(id, words, topic) SELECT id, words, topic FROM group_inserts;
+DROP TABLE group_inserts;
+ALTER TABLE group_inserts_new RENAME TO group_inserts;
+
+CREATE TABLE templates_new (
+    id          INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    template    VARCHAR                              NOT NULL,
+    topic       VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+INSERT INTO templates_new(id, template, topic) SELECT id, template, topic FROM templates;
+DROP TABLE templates;
+ALTER TABLE templates_new RENAME TO templates;
+
+CREATE TABLE answers_new (
+    id         INTEGER   PRIMARY KEY AUTOINCREMENT  NOT NULL,
+    answer     VARCHAR                              NOT NULL,
+    topic      VARCHAR   REFERENCES topics(topic)   NOT NULL
+);
+INSERT INTO answers_new(id, answer, topic) SELECT id, answer, topic FROM answers;
+DROP TABLE answers;
+ALTER TABLE answers_new RENAME TO answers;
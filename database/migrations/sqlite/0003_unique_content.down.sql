@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS single_inserts_topic_word_key;
+DROP INDEX IF EXISTS group_inserts_topic_words_key;
+DROP INDEX IF EXISTS templates_topic_template_key;
+DROP INDEX IF EXISTS answers_topic_answer_key;
@@ -0,0 +1,9 @@
+-- previous seeds could leave duplicated rows behind, only the first of them is kept.
+DELETE FROM single_inserts WHERE id NOT IN (SELECT MIN(id) FROM single_inserts GROUP BY topic, word);
+CREATE UNIQUE INDEX IF NOT EXISTS single_inserts_topic_word_key ON single_inserts(topic, word);
+DELETE FROM group_inserts WHERE id NOT IN (SELECT MIN(id) FROM group_inserts GROUP BY topic, words);
+CREATE UNIQUE INDEX IF NOT EXISTS group_inserts_topic_words_key ON group_inserts(topic, words);
+DELETE FROM templates WHERE id NOT IN (SELECT MIN(id) FROM templates GROUP BY topic, template);
+CREATE UNIQUE INDEX IF NOT EXISTS templates_topic_template_key ON templates(topic, template);
+DELETE FROM answers WHERE id NOT IN (SELECT MIN(id) FROM answers GROUP BY topic, answer);
+CREATE UNIQUE INDEX IF NOT EXISTS answers_topic_answer_key ON answers(topic, answer);
@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS answers_delete_content_changed;
+DROP TRIGGER IF EXISTS answers_update_content_changed;
+DROP TRIGGER IF EXISTS answers_insert_content_changed;
+DROP TRIGGER IF EXISTS templates_delete_content_changed;
+DROP TRIGGER IF EXISTS templates_update_content_changed;
+DROP TRIGGER IF EXISTS templates_insert_content_changed;
+DROP TRIGGER IF EXISTS group_inserts_delete_content_changed;
+DROP TRIGGER IF EXISTS group_inserts_update_content_changed;
+DROP TRIGGER IF EXISTS group_inserts_insert_content_changed;
+DROP TRIGGER IF EXISTS single_inserts_delete_content_changed;
+DROP TRIGGER IF EXISTS single_inserts_update_content_changed;
+DROP TRIGGER IF EXISTS single_inserts_insert_content_changed;
+DROP TRIGGER IF EXISTS topics_delete_content_changed;
+DROP TRIGGER IF EXISTS topics_update_content_changed;
+DROP TRIGGER IF EXISTS topics_insert_content_changed;
+DROP TABLE IF EXISTS content_version;
@@ -0,0 +1,37 @@
+-- sqlite has no notifications, so changes bump a version which is polled instead.
+CREATE TABLE IF NOT EXISTS content_version (
+    id        INTEGER   PRIMARY KEY   NOT NULL   CHECK (id = 1),
+    version   INTEGER                 NOT NULL
+);
+INSERT INTO content_version(id, version) VALUES (1, 0);
+
+CREATE TRIGGER topics_insert_content_changed AFTER INSERT ON topics
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER topics_update_content_changed AFTER UPDATE ON topics
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER topics_delete_content_changed AFTER DELETE ON topics
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER single_inserts_insert_content_changed AFTER INSERT ON single_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER single_inserts_update_content_changed AFTER UPDATE ON single_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER single_inserts_delete_content_changed AFTER DELETE ON single_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER group_inserts_insert_content_changed AFTER INSERT ON group_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER group_inserts_update_content_changed AFTER UPDATE ON group_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER group_inserts_delete_content_changed AFTER DELETE ON group_inserts
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER templates_insert_content_changed AFTER INSERT ON templates
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER templates_update_content_changed AFTER UPDATE ON templates
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER templates_delete_content_changed AFTER DELETE ON templates
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER answers_insert_content_changed AFTER INSERT ON answers
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER answers_update_content_changed AFTER UPDATE ON answers
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER answers_delete_content_changed AFTER DELETE ON answers
+    BEGIN UPDATE content_version SET version = version + 1; END;
@@ -0,0 +1 @@
+ALTER TABLE templates ADD COLUMN mode VARCHAR NOT NULL DEFAULT 'contains';
@@ -0,0 +1 @@
+ALTER TABLE templates ADD COLUMN exact BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS entities_delete_content_changed;
+DROP TRIGGER IF EXISTS entities_update_content_changed;
+DROP TRIGGER IF EXISTS entities_insert_content_changed;
+DROP TABLE IF EXISTS entities;
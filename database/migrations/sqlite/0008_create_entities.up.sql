@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS entities (
+    id        INTEGER   PRIMARY KEY AUTOINCREMENT   NOT NULL,
+    entity    VARCHAR                               NOT NULL,
+    value     VARCHAR                               NOT NULL,
+    synonym   VARCHAR                               NOT NULL,
+    UNIQUE (entity, synonym)
+);
+
+CREATE TRIGGER entities_insert_content_changed AFTER INSERT ON entities
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER entities_update_content_changed AFTER UPDATE ON entities
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER entities_delete_content_changed AFTER DELETE ON entities
+    BEGIN UPDATE content_version SET version = version + 1; END;
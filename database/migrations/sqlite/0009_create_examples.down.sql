@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS examples_delete_content_changed;
+DROP TRIGGER IF EXISTS examples_update_content_changed;
+DROP TRIGGER IF EXISTS examples_insert_content_changed;
+DROP TABLE IF EXISTS examples;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS examples (
+    id        INTEGER   PRIMARY KEY AUTOINCREMENT                                      NOT NULL,
+    example   VARCHAR                                                                  NOT NULL,
+    topic     VARCHAR   REFERENCES topics(topic) ON UPDATE CASCADE ON DELETE CASCADE   NOT NULL,
+    UNIQUE (topic, example)
+);
+
+CREATE TRIGGER examples_insert_content_changed AFTER INSERT ON examples
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER examples_update_content_changed AFTER UPDATE ON examples
+    BEGIN UPDATE content_version SET version = version + 1; END;
+CREATE TRIGGER examples_delete_content_changed AFTER DELETE ON examples
+    BEGIN UPDATE content_version SET version = version + 1; END;
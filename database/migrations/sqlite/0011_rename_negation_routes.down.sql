@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS topics_rename_negation_routes;
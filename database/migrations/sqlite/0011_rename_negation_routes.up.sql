@@ -0,0 +1,4 @@
+-- templates route negated matches to counterpart topics by name, so renames are followed here.
+CREATE TRIGGER topics_rename_negation_routes AFTER UPDATE OF topic ON topics
+    WHEN OLD.topic <> NEW.topic AND OLD.topic <> 'suppress'
+    BEGIN UPDATE templates SET negation = NEW.topic WHERE negation = OLD.topic; END;
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// newTestDatabase opens a database in a temporary sqlite file.
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	db, err := New(Config{URL: "sqlite://" + filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// newTestSchema opens a database in a temporary sqlite file with all migrations applied.
+func newTestSchema(t *testing.T) *Database {
+	t.Helper()
+
+	db := newTestDatabase(t)
+	if err := db.CreateSchema(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// appliedVersion returns the latest applied migration version reported by status, 0 if none is applied.
+func appliedVersion(t *testing.T, db *Database) int {
+	t.Helper()
+
+	statuses, err := db.MigrationStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := 0
+	for _, status := range statuses {
+		if status.Applied {
+			version = status.Version
+		}
+	}
+
+	return version
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+
+	migrations, err := loadMigrations(sqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrations[len(migrations)-1].version
+
+	if version := appliedVersion(t, db); version != 0 {
+		t.Fatalf("version = %d, want 0", version)
+	}
+
+	migrateUp := func() {
+		t.Helper()
+
+		done, err := db.MigrateUp(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(done) != len(migrations) {
+			t.Errorf("applied %d migrations, want %d", len(done), len(migrations))
+		}
+		if version := appliedVersion(t, db); version != latest {
+			t.Fatalf("version after up = %d, want %d", version, latest)
+		}
+	}
+
+	migrateUp()
+
+	done, err := db.MigrateDown(ctx, len(migrations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(migrations) {
+		t.Errorf("reverted %d migrations, want %d", len(done), len(migrations))
+	}
+	if version := appliedVersion(t, db); version != 0 {
+		t.Fatalf("version after down = %d, want 0", version)
+	}
+
+	// down migrations leave the schema ready to be migrated up again.
+	migrateUp()
+
+	// pending migrations are not applied twice.
+	if done, err := db.MigrateUp(ctx); err != nil || len(done) != 0 {
+		t.Errorf("migrate up again = %d migrations, %v, want none", len(done), err)
+	}
+	if _, err := db.MigrateDown(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if version := appliedVersion(t, db); version != migrations[len(migrations)-2].version {
+		t.Errorf("version after one step down = %d, want %d", version, migrations[len(migrations)-2].version)
+	}
+}
@@ -0,0 +1,448 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"phatic_dialogue/types"
+)
+
+// errDryRun rolls back the seed transaction in dry run mode.
+var errDryRun = errors.New("dry run")
+
+// SeedOptions defines how a corpus is seeded into the Database.
+type SeedOptions struct {
+	// Reset deletes all existing content before seeding.
+	Reset bool
+	// DryRun reports changes without applying them.
+	DryRun bool
+}
+
+// ChangeAction is what seed did to a row.
+type ChangeAction string
+
+const (
+	// Created marks a row which was created by seed.
+	Created ChangeAction = "create"
+	// Updated marks a row which was updated by seed.
+	Updated ChangeAction = "update"
+	// Deleted marks a row which was deleted by seed.
+	Deleted ChangeAction = "delete"
+)
+
+// Change describes a single row changed by seed.
+type Change struct {
+	Action ChangeAction
+	Table  string
+	Topic  types.Topic
+	Value  string
+}
+
+// Seed puts the corpus into the Database in a single transaction,
+// skipping rows which already exist, and returns made changes.
+func (db *Database) Seed(ctx context.Context, corpus types.Corpus, options SeedOptions) ([]Change, error) {
+	var changes []Change
+	err := db.WithTx(ctx, func(tx *Database) (err error) {
+		if options.Reset {
+			changes, err = tx.reset(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		seeder, err := tx.newSeeder(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, content := range corpus.Contents {
+			err = seeder.seed(ctx, content)
+			if err != nil {
+				return err
+			}
+		}
+		for _, entity := range corpus.Entities {
+			err = seeder.seedEntity(ctx, entity)
+			if err != nil {
+				return err
+			}
+		}
+		changes = append(changes, seeder.changes...)
+
+		if options.DryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if options.DryRun && errors.Is(err, errDryRun) {
+		return changes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// reset deletes all topics, together with all content referencing them, and all entities.
+func (db *Database) reset(ctx context.Context) ([]Change, error) {
+	topics, err := db.Topics().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0, len(topics))
+	for _, topic := range topics {
+		err = db.Topics().Delete(ctx, topic)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, Change{Action: Deleted, Table: "topics", Topic: topic, Value: string(topic)})
+	}
+
+	entities, err := db.Entities().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range entities {
+		err = db.Entities().Delete(ctx, entity.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, Change{Action: Deleted, Table: "entities", Topic: types.Topic(entity.Entity), Value: entity.Synonym})
+	}
+
+	return changes, nil
+}
+
+// seeder creates missing content rows, remembering already existing ones.
+type seeder struct {
+	db      *Database
+	changes []Change
+
+	topics        map[types.Topic]bool
+	templates     map[[2]string]types.Template
+	answers       map[[2]string]bool
+	singleInserts map[[2]string]bool
+	groupInserts  map[[2]string]bool
+	examples      map[[2]string]bool
+	// entities are keyed by entity name and synonym.
+	entities map[[2]string]types.Entity
+}
+
+// newSeeder loads existing rows of the Database into seeder.
+func (db *Database) newSeeder(ctx context.Context) (*seeder, error) {
+	seeder := &seeder{
+		db:            db,
+		topics:        make(map[types.Topic]bool),
+		templates:     make(map[[2]string]types.Template),
+		answers:       make(map[[2]string]bool),
+		singleInserts: make(map[[2]string]bool),
+		groupInserts:  make(map[[2]string]bool),
+		examples:      make(map[[2]string]bool),
+		entities:      make(map[[2]string]types.Entity),
+	}
+
+	topics, err := db.Topics().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		seeder.topics[topic] = true
+	}
+
+	templates, err := db.Templates().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		seeder.templates[key(template.Topic, template.Template)] = template
+	}
+
+	answers, err := db.Answers().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, answer := range answers {
+		seeder.answers[key(answer.Topic, answer.Answer)] = true
+	}
+
+	singleInserts, err := db.SingleInserts().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, singleInsert := range singleInserts {
+		seeder.singleInserts[key(singleInsert.Topic, singleInsert.Word)] = true
+	}
+
+	groupInserts, err := db.GroupInserts().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, groupInsert := range groupInserts {
+		seeder.groupInserts[key(groupInsert.Topic, groupInsert.Words)] = true
+	}
+
+	examples, err := db.Examples().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, example := range examples {
+		seeder.examples[key(example.Topic, example.Example)] = true
+	}
+
+	entities, err := db.Entities().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		seeder.entities[key(types.Topic(entity.Entity), entity.Synonym)] = entity
+	}
+
+	return seeder, nil
+}
+
+// seed creates missing rows of a single topic content.
+func (seeder *seeder) seed(ctx context.Context, content types.Content) error {
+	topic := types.Topic(strings.ToLower(string(content.Topic)))
+
+	if !seeder.topics[topic] {
+		err := seeder.db.Topics().Create(ctx, topic)
+		if err != nil {
+			return err
+		}
+
+		seeder.topics[topic] = true
+		seeder.changes = append(seeder.changes, Change{Action: Created, Table: "topics", Topic: topic, Value: string(topic)})
+	}
+
+	for _, template := range content.Templates {
+		err := seeder.seedTemplate(ctx, topic, template)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, answer := range content.Answers {
+		err := seeder.create(seeder.answers, "answers", topic, answer, func() error {
+			return seeder.db.Answers().Create(ctx, types.Answer{Answer: answer, Topic: topic})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, singleInsert := range content.SingleInserts {
+		err := seeder.create(seeder.singleInserts, "single_inserts", topic, singleInsert, func() error {
+			return seeder.db.SingleInserts().Create(ctx, types.SingleInsert{Word: singleInsert, Topic: topic})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, groupInsert := range content.GroupInserts {
+		err := seeder.create(seeder.groupInserts, "group_inserts", topic, groupInsert, func() error {
+			return seeder.db.GroupInserts().Create(ctx, types.GroupInsert{Words: groupInsert, Topic: topic})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, example := range content.Examples {
+		err := seeder.create(seeder.examples, "examples", topic, example, func() error {
+			return seeder.db.Examples().Create(ctx, types.Example{Example: example, Topic: topic})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedTemplate creates the template, or updates its options if the template exists with other ones.
+func (seeder *seeder) seedTemplate(ctx context.Context, topic types.Topic, template types.Template) error {
+	template.Template = strings.ToLower(template.Template)
+	template.Topic = topic
+	template.Mode = template.Mode.OrDefault()
+	template.Negation = types.Negation(strings.ToLower(string(template.Negation)))
+
+	existing, ok := seeder.templates[key(topic, template.Template)]
+	switch {
+	case !ok:
+		err := seeder.db.Templates().Create(ctx, template)
+		if err != nil {
+			return err
+		}
+
+		seeder.changes = append(seeder.changes, Change{Action: Created, Table: "templates", Topic: topic, Value: template.Template})
+	case existing.Mode != template.Mode || existing.Priority != template.Priority || existing.Exact != template.Exact ||
+		existing.Negation != template.Negation:
+		template.ID = existing.ID
+		err := seeder.db.Templates().Update(ctx, template)
+		if err != nil {
+			return err
+		}
+
+		seeder.changes = append(seeder.changes, Change{Action: Updated, Table: "templates", Topic: topic, Value: template.Template})
+	default:
+		return nil
+	}
+
+	seeder.templates[key(topic, template.Template)] = template
+
+	return nil
+}
+
+// seedEntity creates the entity synonym, or updates its value if the synonym exists with another one.
+func (seeder *seeder) seedEntity(ctx context.Context, entity types.Entity) error {
+	entity = lowerEntity(entity)
+	name := types.Topic(entity.Entity)
+
+	existing, ok := seeder.entities[key(name, entity.Synonym)]
+	switch {
+	case !ok:
+		err := seeder.db.Entities().Create(ctx, entity)
+		if err != nil {
+			return err
+		}
+
+		seeder.changes = append(seeder.changes, Change{Action: Created, Table: "entities", Topic: name, Value: entity.Synonym})
+	case existing.Value != entity.Value:
+		entity.ID = existing.ID
+		err := seeder.db.Entities().Update(ctx, entity)
+		if err != nil {
+			return err
+		}
+
+		seeder.changes = append(seeder.changes, Change{Action: Updated, Table: "entities", Topic: name, Value: entity.Synonym})
+	default:
+		return nil
+	}
+
+	seeder.entities[key(name, entity.Synonym)] = entity
+
+	return nil
+}
+
+// create calls create for the value, unless it already exists in the table.
+func (seeder *seeder) create(existing map[[2]string]bool, table string, topic types.Topic, value string, create func() error) error {
+	value = strings.ToLower(value)
+	if existing[key(topic, value)] {
+		return nil
+	}
+
+	err := create()
+	if err != nil {
+		return err
+	}
+
+	existing[key(topic, value)] = true
+	seeder.changes = append(seeder.changes, Change{Action: Created, Table: table, Topic: topic, Value: value})
+
+	return nil
+}
+
+// key identifies content row by its topic and value.
+func key(topic types.Topic, value string) [2]string {
+	return [2]string{string(topic), value}
+}
+
+// Corpus returns all content of the Database grouped by topic together with all entities,
+// in the order Seed consumes it. All tables are read in a single transaction,
+// so that the corpus never mixes content from before and after a change.
+func (db *Database) Corpus(ctx context.Context) (corpus types.Corpus, err error) {
+	err = db.withReadTx(ctx, func(tx *Database) error {
+		corpus, err = tx.corpus(ctx)
+		return err
+	})
+
+	return corpus, err
+}
+
+// corpus reads all content of the Database.
+func (db *Database) corpus(ctx context.Context) (types.Corpus, error) {
+	topics, err := db.Topics().List(ctx)
+	if err != nil {
+		return types.Corpus{}, err
+	}
+
+	contents := make([]types.Content, 0, len(topics))
+	indexes := make(map[types.Topic]int, len(topics))
+	for _, topic := range topics {
+		indexes[topic] = len(contents)
+		contents = append(contents, types.Content{
+			Topic:         topic,
+			Templates:     []types.Template{},
+			Answers:       []string{},
+			SingleInserts: []string{},
+			GroupInserts:  []string{},
+			Examples:      []string{},
+		})
+	}
+
+	templates, err := db.Templates().List(ctx)
+	if err != nil {
+		return types.Corpus{}, err
+	}
+	for _, template := range templates {
+		content := &contents[indexes[template.Topic]]
+		content.Templates = append(content.Templates, types.Template{
+			Template: template.Template,
+			Mode:     template.Mode,
+			Priority: template.Priority,
+			Exact:    template.Exact,
+			Negation: template.Negation,
+		})
+	}
+
+	answers, err := db.Answers().List(ctx, "")
+	if err != nil {
+		return types.Corpus{}, err
+	}
+	for _, answer := range answers {
+		content := &contents[indexes[answer.Topic]]
+		content.Answers = append(content.Answers, answer.Answer)
+	}
+
+	singleInserts, err := db.SingleInserts().List(ctx, "")
+	if err != nil {
+		return types.Corpus{}, err
+	}
+	for _, singleInsert := range singleInserts {
+		content := &contents[indexes[singleInsert.Topic]]
+		content.SingleInserts = append(content.SingleInserts, singleInsert.Word)
+	}
+
+	groupInserts, err := db.GroupInserts().List(ctx, "")
+	if err != nil {
+		return types.Corpus{}, err
+	}
+	for _, groupInsert := range groupInserts {
+		content := &contents[indexes[groupInsert.Topic]]
+		content.GroupInserts = append(content.GroupInserts, groupInsert.Words)
+	}
+
+	examples, err := db.Examples().List(ctx, "")
+	if err != nil {
+		return types.Corpus{}, err
+	}
+	for _, example := range examples {
+		content := &contents[indexes[example.Topic]]
+		content.Examples = append(content.Examples, example.Example)
+	}
+
+	entities, err := db.Entities().List(ctx, "")
+	if err != nil {
+		return types.Corpus{}, err
+	}
+
+	return types.Corpus{Contents: contents, Entities: entities}, nil
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"phatic_dialogue/corpus"
+	"phatic_dialogue/types"
+)
+
+// testCorpus is a small corpus of a few topics.
+var testCorpus = types.Corpus{
+	Contents: []types.Content{
+		{
+			Topic:         "привітання",
+			Templates:     []types.Template{{Template: "привіт"}, {Template: "добрий день", Mode: types.MatchWhole}},
+			Answers:       []string{"привіт $"},
+			GroupInserts:  []string{", як справи?"},
+			SingleInserts: []string{"друже"},
+			Examples:      []string{"доброго ранку"},
+		},
+		{
+			Topic:     "погода",
+			Templates: []types.Template{{Template: "яка погода (в|у) @city", Negation: types.NegationSuppress}},
+			Answers:   []string{"у {{entity city}} сонячно"},
+		},
+	},
+	Entities: []types.Entity{{Entity: "city", Value: "київ", Synonym: "києві"}},
+}
+
+// rowCounts returns the number of rows in each content table.
+func rowCounts(t *testing.T, db *Database) map[string]int {
+	t.Helper()
+
+	counts := make(map[string]int)
+	for _, table := range []string{"topics", "templates", "answers", "single_inserts", "group_inserts", "entities", "examples"} {
+		var count int
+		if err := db.conn.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM `+table).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+
+		counts[table] = count
+	}
+
+	return counts
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSchema(t)
+
+	changes, err := db.Seed(ctx, testCorpus, SeedOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) == 0 {
+		t.Fatal("seed made no changes")
+	}
+	counts := rowCounts(t, db)
+	want := map[string]int{"topics": 2, "templates": 3, "answers": 2, "single_inserts": 1, "group_inserts": 1, "entities": 1, "examples": 1}
+	if !reflect.DeepEqual(counts, want) {
+		t.Fatalf("rows = %v, want %v", counts, want)
+	}
+
+	// seeding the same corpus again changes nothing.
+	changes, err = db.Seed(ctx, testCorpus, SeedOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("second seed changes = %+v, want none", changes)
+	}
+	if again := rowCounts(t, db); !reflect.DeepEqual(again, counts) {
+		t.Errorf("rows after second seed = %v, want %v", again, counts)
+	}
+}
+
+func TestSeedDryRun(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSchema(t)
+
+	changes, err := db.Seed(ctx, testCorpus, SeedOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) == 0 {
+		t.Error("dry run reports no changes")
+	}
+	for table, count := range rowCounts(t, db) {
+		if count != 0 {
+			t.Errorf("dry run wrote %d rows into %s", count, table)
+		}
+	}
+}
+
+func TestSeedReset(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSchema(t)
+
+	if _, err := db.Seed(ctx, testCorpus, SeedOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	replacement := types.Corpus{
+		Contents: []types.Content{{Topic: "прощання", Templates: []types.Template{{Template: "бувай"}}, Answers: []string{"до зустрічі"}}},
+	}
+	if _, err := db.Seed(ctx, replacement, SeedOptions{Reset: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	corpus, err := db.Corpus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corpus.Contents) != 1 || corpus.Contents[0].Topic != "прощання" || len(corpus.Entities) != 0 {
+		t.Errorf("corpus after reset = %+v, want only прощання", corpus)
+	}
+	counts := rowCounts(t, db)
+	want := map[string]int{"topics": 1, "templates": 1, "answers": 1, "single_inserts": 0, "group_inserts": 0, "entities": 0, "examples": 0}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("rows after reset = %v, want %v", counts, want)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	dialogue, err := corpus.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// export seeds the database and writes its content as a corpus file.
+	export := func(dialogue types.Corpus) []byte {
+		t.Helper()
+
+		db := newTestSchema(t)
+		if _, err := db.Seed(ctx, dialogue, SeedOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		exported, err := db.Corpus(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var data bytes.Buffer
+		if err := corpus.Encode(&data, exported, ".yaml"); err != nil {
+			t.Fatal(err)
+		}
+
+		return data.Bytes()
+	}
+
+	first := export(dialogue)
+	reparsed, err := corpus.Parse(first, ".yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second := export(reparsed); !bytes.Equal(first, second) {
+		t.Errorf("export of the reseeded export differs:\n%s\nwant:\n%s", second, first)
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -10,11 +11,14 @@ import (
 	"phatic_dialogue/types"
 )
 
+// ErrNoSingleInsert indicates that single insert does not exist.
+var ErrNoSingleInsert = errors.New("single insert does not exist")
+
 // SingleInserts provides access to single_inserts db.
 //
 // architecture: Database
 type SingleInserts struct {
-	conn *sql.DB
+	conn *connection
 }
 
 // Create creates singleInsert in the Database.
@@ -27,19 +31,37 @@ func (collectionsDB *SingleInserts) Create(ctx context.Context, singleInsert typ
 	return Error.Wrap(err)
 }
 
+// Get returns single insert by id from the Database.
+func (collectionsDB *SingleInserts) Get(ctx context.Context, id int) (types.SingleInsert, error) {
+	var singleInsert types.SingleInsert
+
+	query := `SELECT id, word, topic
+ 	          FROM single_inserts
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&singleInsert.ID, &singleInsert.Word, &singleInsert.Topic)
+	if errors.Is(err, sql.ErrNoRows) {
+		return singleInsert, ErrNoSingleInsert
+	}
+
+	return singleInsert, Error.Wrap(err)
+}
+
 // List returns all single inserts or by topic from the Database.
 func (collectionsDB *SingleInserts) List(ctx context.Context, topic types.Topic) (_ []types.SingleInsert, err error) {
 	var list []types.SingleInsert
 	var args = make([]any, 0, 1)
 
-	query := `SELECT word, topic
+	query := `SELECT id, word, topic
  	          FROM single_inserts
  	          `
 
 	if len(topic) != 0 {
-		query += `WHERE topic = $1`
+		query += `WHERE topic = $1
+ 	          `
 		args = append(args, topic)
 	}
+	query += `ORDER BY id ASC`
 
 	rows, err := collectionsDB.conn.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -51,7 +73,7 @@ func (collectionsDB *SingleInserts) List(ctx context.Context, topic types.Topic)
 
 	for rows.Next() {
 		var singleInsert types.SingleInsert
-		err := rows.Scan(&singleInsert.Word, &singleInsert.Topic)
+		err := rows.Scan(&singleInsert.ID, &singleInsert.Word, &singleInsert.Topic)
 		if err != nil {
 			return list, Error.Wrap(err)
 		}
@@ -64,3 +86,31 @@ func (collectionsDB *SingleInserts) List(ctx context.Context, topic types.Topic)
 
 	return list, nil
 }
+
+// Update updates single insert and its topic by id in the Database.
+func (collectionsDB *SingleInserts) Update(ctx context.Context, singleInsert types.SingleInsert) error {
+	singleInsert.Word = strings.ToLower(singleInsert.Word)
+	query := `UPDATE single_inserts
+ 	          SET word = $1, topic = $2
+ 	          WHERE id = $3`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, singleInsert.Word, singleInsert.Topic, singleInsert.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoSingleInsert)
+}
+
+// Delete deletes single insert by id from the Database.
+func (collectionsDB *SingleInserts) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM single_inserts
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoSingleInsert)
+}
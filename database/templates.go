@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -10,30 +11,58 @@ import (
 	"phatic_dialogue/types"
 )
 
+var (
+	// ErrNoTemplate indicates that template does not exist.
+	ErrNoTemplate = errors.New("template does not exist")
+	// ErrUnknownMode indicates that template match mode is not known.
+	ErrUnknownMode = errors.New("unknown template match mode")
+)
+
 // Templates provides access to templates db.
 //
 // architecture: Database
 type Templates struct {
-	conn *sql.DB
+	conn *connection
 }
 
 // Create creates template in the Database.
 func (collectionsDB *Templates) Create(ctx context.Context, template types.Template) error {
+	if !template.Mode.Valid() {
+		return Error.Wrap(ErrUnknownMode)
+	}
+
 	template.Template = strings.ToLower(template.Template)
-	query := `INSERT INTO templates(template, topic) VALUES ($1, $2)`
+	template.Negation = types.Negation(strings.ToLower(string(template.Negation)))
+	query := `INSERT INTO templates(template, topic, mode, priority, exact, negation) VALUES ($1, $2, $3, $4, $5, $6)`
 
-	_, err := collectionsDB.conn.ExecContext(ctx, query, template.Template, template.Topic)
+	_, err := collectionsDB.conn.ExecContext(ctx, query, template.Template, template.Topic, template.Mode.OrDefault(), template.Priority, template.Exact, template.Negation)
 
 	return Error.Wrap(err)
 }
 
+// Get returns template by id from the Database.
+func (collectionsDB *Templates) Get(ctx context.Context, id int) (types.Template, error) {
+	var template types.Template
+
+	query := `SELECT id, template, topic, mode, priority, exact, negation
+ 	          FROM templates
+ 	          WHERE id = $1`
+
+	err := collectionsDB.conn.QueryRowContext(ctx, query, id).Scan(&template.ID, &template.Template, &template.Topic, &template.Mode, &template.Priority, &template.Exact, &template.Negation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return template, ErrNoTemplate
+	}
+
+	return template, Error.Wrap(err)
+}
+
 // List returns all templates from the Database.
 func (collectionsDB *Templates) List(ctx context.Context) (_ []types.Template, err error) {
 	var list []types.Template
 
-	query := `SELECT template, topic
+	query := `SELECT id, template, topic, mode, priority, exact, negation
  	          FROM templates
- 	          ORDER BY topic ASC`
+ 	          ORDER BY topic ASC, id ASC`
 
 	rows, err := collectionsDB.conn.QueryContext(ctx, query)
 	if err != nil {
@@ -45,7 +74,7 @@ func (collectionsDB *Templates) List(ctx context.Context) (_ []types.Template, e
 
 	for rows.Next() {
 		var template types.Template
-		err := rows.Scan(&template.Template, &template.Topic)
+		err := rows.Scan(&template.ID, &template.Template, &template.Topic, &template.Mode, &template.Priority, &template.Exact, &template.Negation)
 		if err != nil {
 			return list, Error.Wrap(err)
 		}
@@ -58,3 +87,36 @@ func (collectionsDB *Templates) List(ctx context.Context) (_ []types.Template, e
 
 	return list, nil
 }
+
+// Update updates template, its topic and options by id in the Database.
+func (collectionsDB *Templates) Update(ctx context.Context, template types.Template) error {
+	if !template.Mode.Valid() {
+		return Error.Wrap(ErrUnknownMode)
+	}
+
+	template.Template = strings.ToLower(template.Template)
+	template.Negation = types.Negation(strings.ToLower(string(template.Negation)))
+	query := `UPDATE templates
+ 	          SET template = $1, topic = $2, mode = $3, priority = $4, exact = $5, negation = $6
+ 	          WHERE id = $7`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, template.Template, template.Topic, template.Mode.OrDefault(), template.Priority, template.Exact, template.Negation, template.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoTemplate)
+}
+
+// Delete deletes template by id from the Database.
+func (collectionsDB *Templates) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM templates
+ 	          WHERE id = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoTemplate)
+}
@@ -15,7 +15,7 @@ import (
 //
 // architecture: Database
 type Topics struct {
-	conn *sql.DB
+	conn *connection
 }
 
 // Create creates topic in the Database.
@@ -47,7 +47,8 @@ func (collectionsDB *Topics) List(ctx context.Context) (_ []types.Topic, err err
 	var list []types.Topic
 
 	query := `SELECT topic
- 	          FROM topics`
+ 	          FROM topics
+ 	          ORDER BY topic ASC`
 
 	rows, err := collectionsDB.conn.QueryContext(ctx, query)
 	if err != nil {
@@ -72,3 +73,32 @@ func (collectionsDB *Topics) List(ctx context.Context) (_ []types.Topic, err err
 
 	return list, nil
 }
+
+// Update renames topic in the Database, references to it are renamed as well,
+// including negation routes of templates to it.
+func (collectionsDB *Topics) Update(ctx context.Context, topic, newTopic types.Topic) error {
+	newTopic = types.Topic(strings.ToLower(string(newTopic)))
+	query := `UPDATE topics
+ 	          SET topic = $1
+ 	          WHERE topic = $2`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, newTopic, topic)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoTopic)
+}
+
+// Delete deletes topic from the Database together with its templates, answers and inserts.
+func (collectionsDB *Topics) Delete(ctx context.Context, topic types.Topic) error {
+	query := `DELETE FROM topics
+ 	          WHERE topic = $1`
+
+	result, err := collectionsDB.conn.ExecContext(ctx, query, topic)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return checkAffected(result, ErrNoTopic)
+}
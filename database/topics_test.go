@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestTopicsDeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSchema(t)
+
+	for _, topic := range []types.Topic{"привітання", "погода"} {
+		if err := db.Topics().Create(ctx, topic); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Templates().Create(ctx, types.Template{Template: "про " + string(topic), Topic: topic}); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Answers().Create(ctx, types.Answer{Answer: "ось " + string(topic), Topic: topic}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.Topics().Delete(ctx, "привітання"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Topics().Get(ctx, "привітання"); !errors.Is(err, ErrNoTopic) {
+		t.Errorf("get deleted topic error = %v, want %v", err, ErrNoTopic)
+	}
+
+	templates, err := db.Templates().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Topic != "погода" {
+		t.Errorf("templates = %+v, want only the template of погода", templates)
+	}
+
+	answers, err := db.Answers().List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 1 || answers[0].Topic != "погода" {
+		t.Errorf("answers = %+v, want only the answer of погода", answers)
+	}
+}
@@ -2,66 +2,237 @@ package engine
 
 import (
 	"context"
-	"regexp"
+	"sort"
 	"strings"
+	"unicode"
 
-	"phatic_dialogue/database"
 	"phatic_dialogue/types"
 )
 
 type Analyser struct {
-	templates *database.Templates
+	cache *Cache
 }
 
-func NewAnalyser(templates *database.Templates) *Analyser {
-	return &Analyser{templates: templates}
+func NewAnalyser(cache *Cache) *Analyser {
+	return &Analyser{cache: cache}
 }
 
-func (analyser *Analyser) AnalyseTopics(ctx context.Context, inStr string) []types.Topic {
-	templates, err := analyser.templates.List(ctx)
+// Analyse splits the input into sentences and returns ranked matches of every sentence in order.
+// Sentences which match nothing, or only topics of previous sentences, are left out,
+// unless nothing matches at all, then the unknown topic of the whole input is returned.
+func (analyser *Analyser) Analyse(ctx context.Context, input string) ([][]types.Match, error) {
+	explanations, err := analyser.ExplainInput(ctx, input)
 	if err != nil {
-		return []types.Topic{types.UnknownTopic}
+		return nil, err
 	}
 
-	topics := filterTopics(templates, normalizeSentence(inStr))
-	if len(topics) == 0 {
-		return []types.Topic{types.UnknownTopic}
+	analyses := make([][]types.Match, 0, len(explanations))
+	for _, explanation := range explanations {
+		analyses = append(analyses, explanation.Matches)
 	}
 
-	return topics
+	return analyses, nil
 }
 
-func normalizeSentence(inStr string) string {
-	inStr = strings.ToLower(inStr)
-	var outStr string
-	for i, symb := range inStr {
-		if (symb == '.' || symb == ',' || symb == '!' || symb == '?') && i >= 1 && inStr[i-1] != ' ' {
-			outStr += " " + string(symb)
+// SplitSentences splits the input after marks . ! ? which are followed by a space or the end of the input,
+// so that marks inside of words and numbers do not split it.
+func SplitSentences(input string) []string {
+	var sentences []string
+	runes := []rune(input)
+	start := 0
+	for i, r := range runes {
+		if r != '.' && r != '!' && r != '?' {
 			continue
 		}
-		outStr += string(symb)
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
 	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
 
-	return outStr
+// AnalyseTopics returns matches of the sentence ranked by score, the best one first,
+// with a single match per topic. It returns the unknown topic if nothing matches,
+// and ErrStorageUnavailable if content is not loaded.
+func (analyser *Analyser) AnalyseTopics(ctx context.Context, inStr string) ([]types.Match, error) {
+	explanation, err := analyser.Explain(ctx, inStr)
+
+	return explanation.Matches, err
 }
 
-func filterTopics(templates []types.Template, normalisedSentence string) []types.Topic {
-	topics := make([]types.Topic, 0)
+// classify returns the topic of the sentence found by the classifier of the snapshot if its probability
+// reaches the threshold, or the unknown topic. Typos are corrected to template words,
+// unless the classifier knows the words from examples.
+func (analyser *Analyser) classify(snapshot *Snapshot, prepared, corrected *sentence, input string) types.Match {
+	unknown := types.Match{Topic: types.UnknownTopic, Input: input}
+	if snapshot.classifier == nil {
+		return unknown
+	}
+	stems := prepared.stems
+	if corrected != nil {
+		stems = snapshot.classifier.prefer(prepared.stems, corrected.stems)
+	}
+
+	// negated words and negation particles tell what the sentence is not about.
+	affirmed := make([]string, 0, len(stems))
+	for i, stem := range stems {
+		if prepared.negators[i] < 0 && !negationParticles[prepared.words[i]] {
+			affirmed = append(affirmed, stem)
+		}
+	}
+
+	topic, confidence := snapshot.classifier.Classify(wordStems(affirmed))
+	if topic == types.UnknownTopic || confidence < analyser.cache.config.Threshold {
+		return unknown
+	}
+
+	return types.Match{Topic: topic, Input: input, Confidence: confidence}
+}
+
+// sentence is a normalized sentence prepared for matching.
+type sentence struct {
+	// text is the sentence of stems separated by single spaces.
+	text string
+	// words are the words of the sentence before stemming.
+	words []string
+	// stems are the words of the sentence as they are matched.
+	stems []string
+	// starts and ends are byte offsets of the stems in text.
+	starts, ends []int
+	// negators are indexes of negation particles negating the words, -1 for words which are not negated.
+	negators []int
+}
+
+// newSentence splits the normalized sentence into words and stems them if the stemmer is not nil.
+func newSentence(normalisedSentence string, stemmer *Stemmer) *sentence {
+	sentence := &sentence{words: strings.Fields(normalisedSentence)}
+	sentence.negators = negators(sentence.words)
+	for _, word := range sentence.words {
+		if stemmer != nil {
+			word = stemmer.Stem(word)
+		}
+
+		sentence.stems = append(sentence.stems, word)
+	}
+	sentence.join()
+
+	return sentence
+}
+
+// join builds the text of the sentence from its stems.
+func (sentence *sentence) join() {
+	var text strings.Builder
+	sentence.starts, sentence.ends = nil, nil
+	for i, stem := range sentence.stems {
+		if i > 0 {
+			text.WriteByte(' ')
+		}
+
+		sentence.starts = append(sentence.starts, text.Len())
+		text.WriteString(stem)
+		sentence.ends = append(sentence.ends, text.Len())
+	}
+	sentence.text = text.String()
+}
+
+// original returns words of the sentence before stemming which are at start:end of the text.
+func (sentence *sentence) original(start, end int) string {
+	first := sort.SearchInts(sentence.starts, start)
+	last := sort.SearchInts(sentence.ends, end)
+	if first >= len(sentence.words) || last >= len(sentence.words) || first > last ||
+		sentence.starts[first] != start || sentence.ends[last] != end {
+		return sentence.text[start:end]
+	}
+
+	return strings.Join(sentence.words[first:last+1], " ")
+}
+
+// better reports whether the match ranks above the other one: exact matches rank above fuzzy ones,
+// then matches with higher scores rank above.
+func better(match, other types.Match) bool {
+	if match.Fuzzy != other.Fuzzy {
+		return !match.Fuzzy
+	}
+
+	return match.Score > other.Score
+}
+
+// priorityWeight is the score of a single point of template priority,
+// so that priority outweighs the specificity of templates of common length.
+const priorityWeight = 100
+
+// findCandidates matches all templates in the sentence, in the order of templates.
+// Templates which are not exact are matched in the corrected sentence too, if it is not nil.
+// Negated matches of templates with negation are suppressed or routed to the counterpart topic.
+func findCandidates(templates []compiledTemplate, sentence, corrected *sentence) []Candidate {
+	var candidates []Candidate
 	for _, template := range templates {
-		template.Template = strings.Replace(template.Template, "_", "[а-я0-9]*", -1)  // _ - single insert / one word -> [а-я0-9]*.
-		template.Template = strings.Replace(template.Template, "$", "[а-я0-9 ]*", -1) // $ - group insert / many words -> [а-я0-9 ]*.
-		templateRegEx, err := regexp.Compile(template.Template)
-		if err != nil {
+		matched := sentence
+		slots, span, ok := template.pattern.match(sentence)
+		if !ok && corrected != nil && !template.template.Exact {
+			matched = corrected
+			slots, span, ok = template.pattern.match(corrected)
+		}
+		if !ok {
 			continue
 		}
 
-		if templateRegEx.MatchString(normalisedSentence) {
-			if len(topics) >= 1 && topics[len(topics)-1] == template.Topic {
-				continue
-			}
-			topics = append(topics, template.Topic)
+		candidate := Candidate{
+			Match: types.Match{
+				Topic:    template.template.Topic,
+				Template: template.template,
+				Score:    template.pattern.Specificity() + template.template.Priority*priorityWeight,
+				Slots:    slots,
+				Fuzzy:    matched == corrected,
+			},
+			Pattern: template.pattern.String(),
+			Span:    span,
+		}
+
+		if negation := template.template.Negation; negation != "" && template.pattern.negated(matched) {
+			counterpart, ok := negation.Topic()
+			candidate.Match.Topic, candidate.Match.Negated, candidate.Suppressed = counterpart, ok, !ok
 		}
+
+		candidates = append(candidates, candidate)
 	}
 
-	return topics
+	return candidates
+}
+
+// rankMatches sorts matches of candidates which are not suppressed by score,
+// keeping the best match of each topic. Fuzzy matches are ranked below all exact ones.
+func rankMatches(candidates []Candidate) []types.Match {
+	best := make(map[types.Topic]int)
+	matches := make([]types.Match, 0)
+	for _, candidate := range candidates {
+		if candidate.Suppressed {
+			continue
+		}
+
+		match := candidate.Match
+		index, ok := best[match.Topic]
+		switch {
+		case !ok:
+			best[match.Topic] = len(matches)
+			matches = append(matches, match)
+		case better(match, matches[index]):
+			matches[index] = match
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return better(matches[i], matches[j])
+	})
+
+	return matches
 }
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"phatic_dialogue/database/memory"
+	"phatic_dialogue/types"
+)
+
+func TestExplainInput(t *testing.T) {
+	cache := NewCache(memory.New(testCorpus), slog.Default(), DefaultConfig())
+	if err := cache.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	analyser := NewAnalyser(cache)
+
+	tests := []struct {
+		input  string
+		inputs []string
+		topics []types.Topic
+	}{
+		{
+			input:  "Привіт! Що там? Яка погода у києві?",
+			inputs: []string{"Привіт!", "Яка погода у києві?"},
+			topics: []types.Topic{"привітання", "погода"},
+		},
+		{
+			input:  "Привіт. Привіт!",
+			inputs: []string{"Привіт."},
+			topics: []types.Topic{"привітання"},
+		},
+		{
+			input:  "Абра. Кадабра",
+			inputs: []string{"Абра. Кадабра"},
+			topics: []types.Topic{types.UnknownTopic},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			explanations, err := analyser.ExplainInput(context.Background(), test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var inputs []string
+			var topics []types.Topic
+			for _, explanation := range explanations {
+				inputs = append(inputs, explanation.Input)
+				topics = append(topics, explanation.Matches[0].Topic)
+			}
+			if !reflect.DeepEqual(inputs, test.inputs) || !reflect.DeepEqual(topics, test.topics) {
+				t.Errorf("explained %q %v, want %q %v", inputs, topics, test.inputs, test.topics)
+			}
+
+			analyses, err := analyser.Analyse(context.Background(), test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range analyses {
+				if !reflect.DeepEqual(analyses[i], explanations[i].Matches) {
+					t.Errorf("analysis %d = %+v, want %+v", i, analyses[i], explanations[i].Matches)
+				}
+			}
+		})
+	}
+
+	unloaded := NewAnalyser(NewCache(memory.New(types.Corpus{}), slog.Default(), DefaultConfig()))
+	if _, err := unloaded.ExplainInput(context.Background(), "привіт"); !errors.Is(err, ErrStorageUnavailable) {
+		t.Errorf("error = %v, want %v", err, ErrStorageUnavailable)
+	}
+}
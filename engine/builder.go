@@ -2,81 +2,182 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
-	"phatic_dialogue/database"
 	"phatic_dialogue/types"
 )
 
+var (
+	// ErrNoAnswers indicates that neither the topic nor the unknown topic has answers.
+	ErrNoAnswers = errors.New("no answers for topic")
+	// ErrEmptyInsertPool indicates that an answer has _ or $ while its topic has no inserts for them.
+	ErrEmptyInsertPool = errors.New("insert pool is empty")
+	// ErrNoMatches indicates that there is nothing to answer.
+	ErrNoMatches = errors.New("no matches to answer")
+)
+
 type Builder struct {
-	singleInserts *database.SingleInserts
-	groupInserts  *database.GroupInserts
-	answers       *database.Answers
+	cache *Cache
+
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+func NewBuilder(cache *Cache, random *rand.Rand) *Builder {
+	return &Builder{cache: cache, random: random}
 }
 
-func NewBuilder(singleInserts *database.SingleInserts, groupInserts *database.GroupInserts, answers *database.Answers) *Builder {
-	return &Builder{
-		singleInserts: singleInserts,
-		groupInserts:  groupInserts,
-		answers:       answers,
+// MakeReply answers the best match of every analysed sentence in order, joining answers into a single reply.
+func (builder *Builder) MakeReply(ctx context.Context, analyses [][]types.Match) (string, error) {
+	if len(analyses) == 0 {
+		return "", ErrNoMatches
 	}
+
+	answers := make([]string, 0, len(analyses))
+	for _, matches := range analyses {
+		answer, err := builder.MakeAnswer(ctx, matches)
+		if err != nil {
+			return "", err
+		}
+
+		answers = append(answers, strings.TrimSpace(answer))
+	}
+
+	return strings.Join(answers, " "), nil
 }
 
-func (builder *Builder) MakeAnswer(ctx context.Context, topics []types.Topic) string {
-	if len(topics) == 0 {
-		return "..."
+// MakeAnswer answers the best match, which is the first one of ranked matches.
+func (builder *Builder) MakeAnswer(ctx context.Context, matches []types.Match) (string, error) {
+	if len(matches) == 0 {
+		return "", ErrNoMatches
+	}
+
+	snapshot := builder.cache.Snapshot()
+	if snapshot == nil {
+		return "", ErrStorageUnavailable
 	}
 
-	answer := builder.generateAnswer(ctx, getRandomElement(topics))
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
 
-	return normaliseAnswer(answer)
+	answer, err := builder.generateAnswer(snapshot, matches[0])
+	if err != nil {
+		return "", err
+	}
+
+	return normaliseAnswer(answer), nil
 }
 
 type possibleElements interface {
-	types.Topic | types.SingleInsert | types.GroupInsert | types.Answer
+	types.SingleInsert | types.GroupInsert | types.Answer
 }
 
-func getRandomElement[T possibleElements](elems []T) T {
-	return elems[rand.Intn(len(elems))]
+func getRandomElement[T possibleElements](random *rand.Rand, elems []T) T {
+	return elems[random.Intn(len(elems))]
 }
 
-func (builder *Builder) generateAnswer(ctx context.Context, topic types.Topic) string {
-	answers, err := builder.answers.List(ctx, topic)
+// generateAnswer picks a random answer of the match topic, or of the unknown topic if the topic has none,
+// fills its inserts and expands its references.
+func (builder *Builder) generateAnswer(snapshot *Snapshot, match types.Match) (string, error) {
+	answers := snapshot.answers[match.Topic]
+	if len(answers) == 0 {
+		answers = snapshot.answers[types.UnknownTopic]
+		if len(answers) == 0 {
+			return "", fmt.Errorf("%w %q", ErrNoAnswers, match.Topic)
+		}
+	}
+
+	answer, err := builder.insertWords(snapshot, getRandomElement(builder.random, answers))
 	if err != nil {
-		return "..."
+		return "", err
 	}
 
-	if len(answers) == 0 {
-		answers, err = builder.answers.List(ctx, types.UnknownTopic)
-		if err != nil {
-			return "..."
+	return expandReferences(answer, match), nil
+}
+
+// insertWords replaces $ of the answer with group inserts and _ with single inserts of the answer topic,
+// leaving references intact, since slot names may contain _.
+func (builder *Builder) insertWords(snapshot *Snapshot, answer types.Answer) (string, error) {
+	var filled strings.Builder
+	last := 0
+	spans := append(reference.FindAllStringIndex(answer.Answer, -1), []int{len(answer.Answer), len(answer.Answer)})
+	for _, span := range spans {
+		for _, r := range answer.Answer[last:span[0]] {
+			switch r {
+			case '$': // $ - group insert / many words.
+				groupInserts := snapshot.groupInserts[answer.Topic]
+				if len(groupInserts) == 0 {
+					return "", fmt.Errorf("%w: topic %q has no group inserts for $ of answer %q", ErrEmptyInsertPool, answer.Topic, answer.Answer)
+				}
+
+				filled.WriteString(getRandomElement(builder.random, groupInserts).Words)
+			case '_': // _ - single insert / one word.
+				singleInserts := snapshot.singleInserts[answer.Topic]
+				if len(singleInserts) == 0 {
+					return "", fmt.Errorf("%w: topic %q has no single inserts for _ of answer %q", ErrEmptyInsertPool, answer.Topic, answer.Answer)
+				}
+
+				filled.WriteString(getRandomElement(builder.random, singleInserts).Word)
+			default:
+				filled.WriteRune(r)
+			}
 		}
+
+		filled.WriteString(answer.Answer[span[0]:span[1]])
+		last = span[1]
 	}
 
-	return builder.insertWords(ctx, getRandomElement(answers))
+	return filled.String(), nil
 }
 
-func (builder *Builder) insertWords(ctx context.Context, answer types.Answer) string {
-	for strings.Contains(answer.Answer, "$") { // $ - group insert / many words -> [а-я0-9 ]*.
-		groupInserts, err := builder.groupInserts.List(ctx, answer.Topic)
-		if err != nil {
-			return "..."
+// reference is a reference to the user input in an answer:
+//
+//	{{slot 1}}       value of the first slot of the matched template
+//	{{slot name}}    value of the slot named name
+//	{{entity name}}  canonical value of the entity matched by the slot named name
+//	{{input}}        the whole sentence of the user
+var reference = regexp.MustCompile(`\{\{\s*(?:(slot|entity)\s+([^\s}]+)|(input))\s*\}\}`)
+
+func expandReferences(answer string, match types.Match) string {
+	return reference.ReplaceAllStringFunc(answer, func(ref string) string {
+		groups := reference.FindStringSubmatch(ref)
+		if groups[3] != "" {
+			return match.Input
 		}
 
-		answer.Answer = strings.Replace(answer.Answer, "$", getRandomElement(groupInserts).Words, 1)
-	}
+		slot := findSlot(match.Slots, groups[2])
+		if groups[1] == "entity" {
+			return slot.Canonical
+		}
 
-	for strings.Contains(answer.Answer, "_") { // _ - single insert / one word -> [а-я0-9]*.
-		singleInserts, err := builder.singleInserts.List(ctx, answer.Topic)
-		if err != nil {
-			return "..."
+		return slot.Value
+	})
+}
+
+// findSlot returns the slot by its 1-based position or by its name, or an empty slot if there is none.
+// Of several slots with the same name the first matched one is taken.
+func findSlot(slots []types.Slot, key string) types.Slot {
+	if position, err := strconv.Atoi(key); err == nil {
+		if position < 1 || position > len(slots) {
+			return types.Slot{}
 		}
 
-		answer.Answer = strings.Replace(answer.Answer, "_", getRandomElement(singleInserts).Word, 1)
+		return slots[position-1]
+	}
+
+	for _, slot := range slots {
+		if slot.Name == key && slot.Value != "" {
+			return slot
+		}
 	}
 
-	return answer.Answer
+	return types.Slot{}
 }
 
 func normaliseAnswer(answer string) string {
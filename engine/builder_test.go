@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestGenerateAnswer(t *testing.T) {
+	pattern, err := CompileTemplate(types.Template{Template: "cook _:dish_name"}, nil, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	slots, _, ok := pattern.match(newSentence(DefaultPipeline().Normalize("Cook pasta"), nil))
+	if !ok {
+		t.Fatal("template does not match")
+	}
+	match := types.Match{Topic: "cooking", Input: "Cook pasta", Slots: slots}
+
+	tests := []struct {
+		name          string
+		answer        string
+		singleInserts []types.SingleInsert
+		groupInserts  []types.GroupInsert
+		want          string
+		err           error
+	}{
+		{
+			name:   "slot name with underscore",
+			answer: "try {{slot dish_name}}",
+			want:   "try pasta",
+		},
+		{
+			name:          "inserts around references",
+			answer:        "_ {{slot dish_name}}, $ {{ slot 1 }}",
+			singleInserts: []types.SingleInsert{{Word: "tasty", Topic: "cooking"}},
+			groupInserts:  []types.GroupInsert{{Words: "with cheese", Topic: "cooking"}},
+			want:          "tasty pasta, with cheese pasta",
+		},
+		{
+			name:   "input reference",
+			answer: "you said {{input}}",
+			want:   "you said Cook pasta",
+		},
+		{
+			name:   "empty single insert pool",
+			answer: "try _ {{slot dish_name}}",
+			err:    ErrEmptyInsertPool,
+		},
+		{
+			name:   "empty group insert pool",
+			answer: "try $",
+			err:    ErrEmptyInsertPool,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot := &Snapshot{
+				answers:       map[types.Topic][]types.Answer{"cooking": {{Answer: test.answer, Topic: "cooking"}}},
+				singleInserts: map[types.Topic][]types.SingleInsert{"cooking": test.singleInserts},
+				groupInserts:  map[types.Topic][]types.GroupInsert{"cooking": test.groupInserts},
+			}
+			builder := NewBuilder(nil, rand.New(rand.NewSource(1)))
+
+			answer, err := builder.generateAnswer(snapshot, match)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if answer != test.want {
+				t.Errorf("answer = %q, want %q", answer, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"phatic_dialogue/types"
+)
+
+// ErrStorageUnavailable indicates that content could not be read from the storage,
+// or that it has not been read yet.
+var ErrStorageUnavailable = errors.New("storage is unavailable")
+
+// Snapshot is an immutable copy of all dialogue content with precompiled templates.
+type Snapshot struct {
+	templates []compiledTemplate
+	// vocabulary are sorted literal words of templates which are not exact, typos are corrected to them.
+	vocabulary    []string
+	answers       map[types.Topic][]types.Answer
+	singleInserts map[types.Topic][]types.SingleInsert
+	groupInserts  map[types.Topic][]types.GroupInsert
+	// classifier finds topics of sentences no template matches, it is nil if the classifier is off.
+	classifier *Classifier
+}
+
+// compiledTemplate is a template together with its compiled pattern.
+type compiledTemplate struct {
+	template types.Template
+	pattern  *Pattern
+}
+
+// Cache keeps the latest Snapshot of the dialogue content in process,
+// so that analysing and answering a sentence does no storage round-trips.
+type Cache struct {
+	content Content
+	logger  *slog.Logger
+	config  Config
+
+	snapshot atomic.Pointer[Snapshot]
+}
+
+// NewCache is a constructor for Cache, Reload must be called to fill it.
+func NewCache(content Content, logger *slog.Logger, config Config) *Cache {
+	return &Cache{content: content, logger: logger, config: config}
+}
+
+// Snapshot returns the latest loaded snapshot, or nil if the Cache is not loaded yet.
+func (cache *Cache) Snapshot() *Snapshot {
+	return cache.snapshot.Load()
+}
+
+// Classifier returns the classifier of the snapshot, or nil if the classifier is off.
+func (snapshot *Snapshot) Classifier() *Classifier {
+	return snapshot.classifier
+}
+
+// Reload reads all content from the storage and atomically replaces the snapshot.
+// The previous snapshot is kept if reading fails, while invalid templates are logged and skipped.
+func (cache *Cache) Reload(ctx context.Context) error {
+	snapshot, err := cache.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	cache.snapshot.Store(snapshot)
+
+	return nil
+}
+
+// load reads all content from the storage into a new snapshot.
+func (cache *Cache) load(ctx context.Context) (*Snapshot, error) {
+	corpus, err := cache.content.Corpus(ctx)
+	if err != nil {
+		return nil, unavailable(err)
+	}
+
+	snapshot := &Snapshot{
+		answers:       make(map[types.Topic][]types.Answer),
+		singleInserts: make(map[types.Topic][]types.SingleInsert),
+		groupInserts:  make(map[types.Topic][]types.GroupInsert),
+	}
+
+	var templates []types.Template
+	var examples []types.Example
+	for _, content := range corpus.Contents {
+		topic := content.Topic
+		for _, template := range content.Templates {
+			template.Topic = topic
+			templates = append(templates, template)
+		}
+		for _, answer := range content.Answers {
+			snapshot.answers[topic] = append(snapshot.answers[topic], types.Answer{Answer: answer, Topic: topic})
+		}
+		for _, word := range content.SingleInserts {
+			snapshot.singleInserts[topic] = append(snapshot.singleInserts[topic], types.SingleInsert{Word: word, Topic: topic})
+		}
+		for _, words := range content.GroupInserts {
+			snapshot.groupInserts[topic] = append(snapshot.groupInserts[topic], types.GroupInsert{Words: words, Topic: topic})
+		}
+		for _, example := range content.Examples {
+			examples = append(examples, types.Example{Example: example, Topic: topic})
+		}
+	}
+
+	snapshot.templates = cache.compileTemplates(templates, NewEntityLists(corpus.Entities))
+	snapshot.vocabulary = vocabulary(snapshot.templates)
+
+	snapshot.classifier, err = cache.classifier(snapshot.templates, examples)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// classifier returns the configured classifier, or trains one from templates and examples.
+// The configured classifier is not retrained, it must be trained again after content changes.
+func (cache *Cache) classifier(templates []compiledTemplate, examples []types.Example) (*Classifier, error) {
+	if cache.config.Threshold > 1 {
+		return nil, nil
+	}
+
+	if classifier := cache.config.Classifier; classifier != nil {
+		if name := stemmerName(cache.config.Stemmer); classifier.Stemmer != name {
+			return nil, fmt.Errorf("%w %q, not %q", ErrClassifierStemmer, classifier.Stemmer, name)
+		}
+		if names := cache.config.Normalizers.Names(); !slices.Equal(classifier.Normalizers, names) {
+			return nil, fmt.Errorf("%w %v, not %v", ErrClassifierNormalizers, classifier.Normalizers, names)
+		}
+
+		cache.logger.Info("classifier model is not retrained on reload")
+
+		return classifier, nil
+	}
+
+	return train(templates, examples, cache.config), nil
+}
+
+// unavailable wraps the error of the storage into ErrStorageUnavailable.
+func unavailable(err error) error {
+	return fmt.Errorf("%w: %w", ErrStorageUnavailable, err)
+}
+
+// compileTemplates compiles templates into regular expressions,
+// so that one invalid template does not keep the rest of the content from loading.
+func (cache *Cache) compileTemplates(templates []types.Template, entities EntityLists) []compiledTemplate {
+	compiled := make([]compiledTemplate, 0, len(templates))
+	for _, template := range templates {
+		pattern, err := CompileTemplate(template, entities, cache.config)
+		if err != nil {
+			cache.logger.Error("template is skipped", "topic", template.Topic, "error", err)
+			continue
+		}
+
+		compiled = append(compiled, compiledTemplate{template: template, pattern: pattern})
+	}
+
+	return compiled
+}
@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"phatic_dialogue/database/memory"
+	"phatic_dialogue/types"
+)
+
+// failingContent is a Content which fails with the error when it is set.
+type failingContent struct {
+	Content
+	err error
+}
+
+func (content *failingContent) Corpus(ctx context.Context) (types.Corpus, error) {
+	if content.err != nil {
+		return types.Corpus{}, content.err
+	}
+
+	return content.Content.Corpus(ctx)
+}
+
+// testCorpus is a small corpus of a few topics.
+var testCorpus = types.Corpus{
+	Contents: []types.Content{
+		{
+			Topic:         "привітання",
+			Templates:     []types.Template{{Template: "привіт"}, {Template: "добрий день", Mode: types.MatchWhole}},
+			Answers:       []string{"привіт $"},
+			GroupInserts:  []string{", як справи?"},
+			SingleInserts: []string{"друже"},
+		},
+		{
+			Topic:     "погода",
+			Templates: []types.Template{{Template: "яка погода (в|у) @city"}},
+			Answers:   []string{"у {{entity city}} сонячно"},
+			Examples:  []string{"чи буде дощ"},
+		},
+		{
+			Topic:   types.UnknownTopic,
+			Answers: []string{"не знаю"},
+		},
+	},
+	Entities: []types.Entity{{Entity: "city", Value: "київ", Synonym: "києві"}},
+}
+
+func TestCacheClassifierModel(t *testing.T) {
+	model := testClassifier(t, DefaultConfig())
+
+	tests := []struct {
+		name   string
+		config func(config *Config)
+		err    error
+	}{
+		{name: "same options", config: func(config *Config) {}},
+		{name: "other stemmer", config: func(config *Config) { config.Stemmer = &Stemmer{Name: "uk"} }, err: ErrClassifierStemmer},
+		{name: "other normalizers", config: func(config *Config) { config.Normalizers = Pipeline{Lower} }, err: ErrClassifierNormalizers},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Classifier = model
+			test.config(&config)
+
+			cache := NewCache(memory.New(testCorpus), slog.New(slog.NewTextHandler(io.Discard, nil)), config)
+			if err := cache.Reload(context.Background()); !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if test.err == nil && cache.Snapshot().Classifier() != model {
+				t.Error("configured classifier is retrained")
+			}
+		})
+	}
+}
+
+func TestCacheReload(t *testing.T) {
+	store := memory.New(testCorpus)
+	content := &failingContent{Content: store}
+	var log bytes.Buffer
+	cache := NewCache(content, slog.New(slog.NewTextHandler(&log, nil)), DefaultConfig())
+	if cache.Snapshot() != nil {
+		t.Fatal("snapshot is loaded before Reload")
+	}
+	if err := cache.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := cache.Snapshot()
+	if len(snapshot.templates) != 3 {
+		t.Fatalf("templates = %d, want 3", len(snapshot.templates))
+	}
+	if topic := snapshot.templates[2].template.Topic; topic != "погода" {
+		t.Errorf("template topic = %q, want погода", topic)
+	}
+	if len(snapshot.answers["привітання"]) != 1 || len(snapshot.groupInserts["привітання"]) != 1 ||
+		len(snapshot.singleInserts["привітання"]) != 1 || len(snapshot.answers[types.UnknownTopic]) != 1 {
+		t.Errorf("answers and inserts are not grouped by topic: %+v", snapshot)
+	}
+	if snapshot.Classifier() == nil {
+		t.Error("classifier is not trained")
+	}
+
+	// a failed reload keeps the previous snapshot.
+	content.err = errors.New("connection refused")
+	if err := cache.Reload(context.Background()); !errors.Is(err, ErrStorageUnavailable) {
+		t.Errorf("error = %v, want %v", err, ErrStorageUnavailable)
+	}
+	if cache.Snapshot() != snapshot {
+		t.Error("snapshot is replaced by a failed reload")
+	}
+
+	// invalid templates are logged and skipped, keeping the rest of the content.
+	content.err = nil
+	store.Replace(types.Corpus{Contents: []types.Content{{Topic: "t", Templates: []types.Template{{Template: "[a"}, {Template: "b"}}}}})
+	if err := cache.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if templates := cache.Snapshot().templates; len(templates) != 1 || templates[0].template.Template != "b" {
+		t.Errorf("templates = %+v, want only b", templates)
+	}
+	if !strings.Contains(log.String(), "template is skipped") || !strings.Contains(log.String(), `\"[a\"`) {
+		t.Errorf("log = %q, want the skipped template", log.String())
+	}
+}
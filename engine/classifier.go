@@ -0,0 +1,255 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"phatic_dialogue/types"
+)
+
+var (
+	// ErrClassifierStemmer indicates that the classifier was trained with another stemmer than the configured one.
+	ErrClassifierStemmer = errors.New("classifier is trained with another stemmer")
+	// ErrClassifierNormalizers indicates that the classifier was trained with other normalizers than the configured ones.
+	ErrClassifierNormalizers = errors.New("classifier is trained with other normalizers")
+)
+
+// smoothing is the weight added to every term of every topic, so that unseen terms do not rule topics out.
+const smoothing = 0.1
+
+// Classifier is a naive Bayes classifier of sentence topics over tf-idf weighted stems,
+// trained from words of templates and from examples.
+// It is used when no template matches a sentence.
+type Classifier struct {
+	// Stemmer is the name of the stemmer the classifier was trained with.
+	Stemmer string `json:"stemmer"`
+	// Normalizers are names of the normalizers the classifier was trained with, in order.
+	Normalizers []string `json:"normalizers"`
+	// IDF is the inverse document frequency of every known stem.
+	IDF map[string]float64 `json:"idf"`
+	// Topics are sorted by topic.
+	Topics []ClassifierTopic `json:"topics"`
+}
+
+// ClassifierTopic is the log probability of the topic and of stems in its sentences.
+type ClassifierTopic struct {
+	Topic types.Topic `json:"topic"`
+	Prior float64     `json:"prior"`
+	// Stems are log probabilities of known stems, stems which are missing have the Unseen one.
+	Stems  map[string]float64 `json:"stems"`
+	Unseen float64            `json:"unseen"`
+}
+
+// train builds the classifier from templates which are not of the unknown topic and from examples,
+// each template and each example being a single document of its topic,
+// together with the background class of the unknown topic.
+func train(templates []compiledTemplate, examples []types.Example, config Config) *Classifier {
+	var documents []types.Topic
+	var terms [][]string
+	for _, template := range templates {
+		if template.template.Topic == types.UnknownTopic || len(template.pattern.words) == 0 {
+			continue
+		}
+
+		documents = append(documents, template.template.Topic)
+		terms = append(terms, template.pattern.words)
+	}
+	for _, example := range examples {
+		stems := wordStems(newSentence(config.Normalizers.Normalize(example.Example), config.Stemmer).stems)
+		if len(stems) == 0 {
+			continue
+		}
+
+		documents = append(documents, example.Topic)
+		terms = append(terms, stems)
+	}
+
+	classifier := &Classifier{Stemmer: stemmerName(config.Stemmer), Normalizers: config.Normalizers.Names(), IDF: make(map[string]float64)}
+	if len(documents) == 0 {
+		return classifier
+	}
+
+	frequency := make(map[string]int)
+	for _, stems := range terms {
+		for stem := range termCounts(stems) {
+			frequency[stem]++
+		}
+	}
+	for stem, count := range frequency {
+		classifier.IDF[stem] = math.Log(1 + float64(len(documents))/float64(count))
+	}
+
+	counts := make(map[types.Topic]int)
+	weights := make(map[types.Topic]map[string]float64)
+	for i, topic := range documents {
+		counts[topic]++
+		if weights[topic] == nil {
+			weights[topic] = make(map[string]float64)
+		}
+
+		for stem, count := range termCounts(terms[i]) {
+			weights[topic][stem] += math.Log(1+float64(count)) * classifier.IDF[stem]
+		}
+	}
+
+	// the background class of the unknown topic has stems of all topics, so that it wins sentences
+	// of common words which do not tell one topic from another, and stems of unknown topic examples.
+	background := make(map[string]float64)
+	topics := 0
+	for topic, stems := range weights {
+		if topic == types.UnknownTopic {
+			continue
+		}
+
+		topics++
+		for stem, weight := range stems {
+			background[stem] += weight
+		}
+	}
+	for stem, weight := range background {
+		background[stem] = weight / float64(topics)
+	}
+	for stem, weight := range weights[types.UnknownTopic] {
+		background[stem] += weight
+	}
+	weights[types.UnknownTopic] = background
+	counts[types.UnknownTopic] += len(documents) / max(topics, 1)
+
+	for topic, stems := range weights {
+		total := 0.0
+		for _, weight := range stems {
+			total += weight
+		}
+		denominator := total + smoothing*float64(len(classifier.IDF))
+
+		class := ClassifierTopic{
+			Topic:  topic,
+			Prior:  math.Log(float64(max(counts[topic], 1)) / float64(len(documents))),
+			Stems:  make(map[string]float64, len(stems)),
+			Unseen: math.Log(smoothing / denominator),
+		}
+		for stem, weight := range stems {
+			class.Stems[stem] = math.Log((weight + smoothing) / denominator)
+		}
+
+		classifier.Topics = append(classifier.Topics, class)
+	}
+	sort.Slice(classifier.Topics, func(i, j int) bool {
+		return classifier.Topics[i].Topic < classifier.Topics[j].Topic
+	})
+
+	return classifier
+}
+
+// Classify returns the most probable topic of the stems and its probability.
+// Stems the classifier does not know are ignored, the unknown topic is returned if all of them are unknown
+// or if they are common to many topics.
+func (classifier *Classifier) Classify(stems []string) (types.Topic, float64) {
+	var known []string
+	for _, stem := range stems {
+		if _, ok := classifier.IDF[stem]; ok {
+			known = append(known, stem)
+		}
+	}
+	if len(known) == 0 || len(classifier.Topics) == 0 {
+		return types.UnknownTopic, 0
+	}
+
+	scores := make([]float64, len(classifier.Topics))
+	best := 0
+	for i, class := range classifier.Topics {
+		scores[i] = class.Prior
+		for _, stem := range known {
+			probability, ok := class.Stems[stem]
+			if !ok {
+				probability = class.Unseen
+			}
+
+			scores[i] += probability
+		}
+
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+
+	return classifier.Topics[best].Topic, 1 / sum
+}
+
+// LoadClassifier reads the classifier saved by Save.
+func LoadClassifier(path string) (*Classifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var classifier Classifier
+	if err = json.Unmarshal(data, &classifier); err != nil {
+		return nil, fmt.Errorf("classifier %s: %w", path, err)
+	}
+
+	return &classifier, nil
+}
+
+// Save writes the classifier into the file as json.
+func (classifier *Classifier) Save(path string) error {
+	data, err := json.Marshal(classifier)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// prefer returns the corrected stems, keeping the original ones which the classifier knows.
+func (classifier *Classifier) prefer(stems, corrected []string) []string {
+	preferred := make([]string, len(stems))
+	for i, stem := range stems {
+		preferred[i] = corrected[i]
+		if _, ok := classifier.IDF[stem]; ok {
+			preferred[i] = stem
+		}
+	}
+
+	return preferred
+}
+
+// wordStems returns the stems which are words, leaving punctuation out.
+func wordStems(stems []string) []string {
+	var words []string
+	for _, stem := range stems {
+		if isWord([]rune(stem)) {
+			words = append(words, stem)
+		}
+	}
+
+	return words
+}
+
+// termCounts counts occurrences of every stem.
+func termCounts(stems []string) map[string]int {
+	counts := make(map[string]int, len(stems))
+	for _, stem := range stems {
+		counts[stem]++
+	}
+
+	return counts
+}
+
+// stemmerName returns the name of the stemmer, or NoStemmer if it is nil.
+func stemmerName(stemmer *Stemmer) string {
+	if stemmer == nil {
+		return NoStemmer
+	}
+
+	return stemmer.Name
+}
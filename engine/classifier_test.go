@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+// testClassifier trains the classifier from a small corpus of two topics.
+func testClassifier(t *testing.T, config Config) *Classifier {
+	t.Helper()
+
+	var templates []compiledTemplate
+	for _, template := range []types.Template{
+		{Template: "порадь фільм", Topic: "фільми"},
+		{Template: "який фільм подивитись", Topic: "фільми"},
+		{Template: "порадь книгу", Topic: "книги"},
+		{Template: "що почитати", Topic: "книги"},
+	} {
+		pattern, err := CompileTemplate(template, nil, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		templates = append(templates, compiledTemplate{template: template, pattern: pattern})
+	}
+
+	examples := []types.Example{
+		{Example: "увімкни якесь кіно", Topic: "фільми"},
+		{Example: "хочу гарний роман", Topic: "книги"},
+		{Example: "яка сьогодні погода", Topic: types.UnknownTopic},
+	}
+
+	return train(templates, examples, config)
+}
+
+func TestClassify(t *testing.T) {
+	config := DefaultConfig()
+	classifier := testClassifier(t, config)
+
+	tests := []struct {
+		sentence string
+		topic    types.Topic
+	}{
+		{sentence: "увімкни кіно", topic: "фільми"},
+		{sentence: "гарний роман на вечір", topic: "книги"},
+		// порадь is common to both topics, so neither of them reaches the threshold.
+		{sentence: "порадь", topic: types.UnknownTopic},
+		{sentence: "яка погода", topic: types.UnknownTopic},
+		{sentence: "зовсім невідомі слова", topic: types.UnknownTopic},
+	}
+	analyser := NewAnalyser(&Cache{config: config})
+	snapshot := &Snapshot{classifier: classifier}
+	for _, test := range tests {
+		prepared := newSentence(config.Normalizers.Normalize(test.sentence), config.Stemmer)
+		if match := analyser.classify(snapshot, prepared, nil, test.sentence); match.Topic != test.topic {
+			t.Errorf("classify(%q) = %q, want %q", test.sentence, match.Topic, test.topic)
+		}
+	}
+}
+
+func TestClassifyThreshold(t *testing.T) {
+	config := DefaultConfig()
+	snapshot := &Snapshot{classifier: testClassifier(t, config)}
+	prepared := newSentence(config.Normalizers.Normalize("увімкни кіно"), config.Stemmer)
+
+	_, confidence := snapshot.classifier.Classify(prepared.stems)
+	if confidence <= 0.5 || confidence >= 1 {
+		t.Fatalf("confidence = %v, want between 0.5 and 1", confidence)
+	}
+
+	tests := []struct {
+		threshold float64
+		topic     types.Topic
+	}{
+		{threshold: 0.5, topic: "фільми"},
+		{threshold: confidence, topic: "фільми"},
+		{threshold: (confidence + 1) / 2, topic: types.UnknownTopic},
+	}
+	for _, test := range tests {
+		config.Threshold = test.threshold
+		analyser := NewAnalyser(&Cache{config: config})
+
+		match := analyser.classify(snapshot, prepared, nil, "увімкни кіно")
+		if match.Topic != test.topic {
+			t.Errorf("threshold %v: topic = %q, want %q", test.threshold, match.Topic, test.topic)
+		}
+		if match.Topic != types.UnknownTopic && match.Confidence != confidence {
+			t.Errorf("threshold %v: confidence = %v, want %v", test.threshold, match.Confidence, confidence)
+		}
+	}
+
+	// negated words are not classified.
+	negated := newSentence(config.Normalizers.Normalize("не увімкни кіно"), config.Stemmer)
+	if match := NewAnalyser(&Cache{config: DefaultConfig()}).classify(snapshot, negated, nil, "не увімкни кіно"); match.Topic != types.UnknownTopic {
+		t.Errorf("negated topic = %q, want %q", match.Topic, types.UnknownTopic)
+	}
+}
+
+func TestClassifierSave(t *testing.T) {
+	classifier := testClassifier(t, DefaultConfig())
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := classifier.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadClassifier(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.Normalizers, DefaultPipeline().Names()) {
+		t.Errorf("loaded normalizers = %v, want %v", loaded.Normalizers, DefaultPipeline().Names())
+	}
+
+	stems := []string{"увімкни", "кіно"}
+	topic, confidence := classifier.Classify(stems)
+	loadedTopic, loadedConfidence := loaded.Classify(stems)
+	if topic != loadedTopic || confidence != loadedConfidence {
+		t.Errorf("loaded classifier = %q %v, want %q %v", loadedTopic, loadedConfidence, topic, confidence)
+	}
+}
@@ -0,0 +1,25 @@
+package engine
+
+// Config defines how templates are compiled and matched.
+type Config struct {
+	// Language defines characters of words matched by template slots.
+	Language Language
+	// Stemmer reduces words of templates and sentences to stems, stemming is off if it is nil.
+	Stemmer *Stemmer
+	// Fuzzy corrects typos of sentences to words of templates which are not exact.
+	Fuzzy bool
+	// Classifier is the trained classifier used when no template matches,
+	// it is trained from the content on every reload if it is nil.
+	Classifier *Classifier
+	// Threshold is the least probability of the classified topic to be answered,
+	// the classifier is off if it is above 1.
+	Threshold float64
+	// Normalizers prepare sentences and words of templates for matching the same way.
+	Normalizers Pipeline
+}
+
+// DefaultConfig returns configuration which matches words of any script, tolerating typos,
+// and classifies sentences no template matches.
+func DefaultConfig() Config {
+	return Config{Language: Unicode, Fuzzy: true, Threshold: 0.5, Normalizers: DefaultPipeline()}
+}
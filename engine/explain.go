@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"phatic_dialogue/types"
+)
+
+// Explanation is the trace of how the Analyser matched a sentence.
+type Explanation struct {
+	// Input is the sentence as the user wrote it.
+	Input string
+	// Normalized is the sentence as templates are matched in it, stemmed if stemming is on.
+	Normalized string
+	// Corrected is the normalized sentence with typos corrected, it is empty if no typo is corrected.
+	Corrected string
+	// Candidates are all templates which match the sentence, in the order of templates.
+	Candidates []Candidate
+	// Matches are the ranked matches of the sentence, as AnalyseTopics returns them.
+	Matches []types.Match
+}
+
+// Candidate is a template which matches the sentence.
+type Candidate struct {
+	// Match is the match of the template, routed to the counterpart topic if it is negated.
+	Match types.Match
+	// Pattern is the regular expression the template is compiled into.
+	Pattern string
+	// Span is the part of the normalized or corrected sentence matched by the pattern.
+	Span string
+	// Suppressed reports whether the match is dropped, since it is negated.
+	Suppressed bool
+}
+
+// Explain analyses the sentence the way AnalyseTopics does and returns the trace of it.
+func (analyser *Analyser) Explain(ctx context.Context, inStr string) (Explanation, error) {
+	snapshot := analyser.cache.Snapshot()
+	if snapshot == nil {
+		return Explanation{Input: strings.TrimSpace(inStr)}, ErrStorageUnavailable
+	}
+
+	return analyser.explain(snapshot, inStr), nil
+}
+
+// ExplainInput analyses the input the way Analyse does and returns traces of the sentences it answers,
+// or the trace of the whole input if no sentence is answered. All sentences are matched in the same snapshot.
+func (analyser *Analyser) ExplainInput(ctx context.Context, input string) ([]Explanation, error) {
+	snapshot := analyser.cache.Snapshot()
+	if snapshot == nil {
+		return nil, ErrStorageUnavailable
+	}
+
+	var explanations []Explanation
+	answered := make(map[types.Topic]bool)
+	for _, sentence := range SplitSentences(input) {
+		explanation := analyser.explain(snapshot, sentence)
+		if best := explanation.Matches[0].Topic; best == types.UnknownTopic || answered[best] {
+			continue
+		}
+
+		answered[explanation.Matches[0].Topic] = true
+		explanations = append(explanations, explanation)
+	}
+	if len(explanations) == 0 {
+		return []Explanation{analyser.explain(snapshot, input)}, nil
+	}
+
+	return explanations, nil
+}
+
+// explain matches the sentence in the snapshot.
+func (analyser *Analyser) explain(snapshot *Snapshot, inStr string) Explanation {
+	input := strings.TrimSpace(inStr)
+	explanation := Explanation{Input: input}
+
+	prepared := newSentence(analyser.cache.config.Normalizers.Normalize(inStr), analyser.cache.config.Stemmer)
+	explanation.Normalized = prepared.text
+
+	var corrected *sentence
+	if analyser.cache.config.Fuzzy {
+		corrected = prepared.correct(snapshot.vocabulary)
+	}
+	if corrected != nil {
+		explanation.Corrected = corrected.text
+	}
+
+	explanation.Candidates = findCandidates(snapshot.templates, prepared, corrected)
+	for i := range explanation.Candidates {
+		explanation.Candidates[i].Match.Input = input
+	}
+
+	explanation.Matches = rankMatches(explanation.Candidates)
+	if len(explanation.Matches) == 0 {
+		explanation.Matches = []types.Match{analyser.classify(snapshot, prepared, corrected, input)}
+	}
+
+	return explanation
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// vocabulary returns sorted unique literal words of templates which are not exact.
+func vocabulary(templates []compiledTemplate) []string {
+	unique := make(map[string]bool)
+	for _, template := range templates {
+		if template.template.Exact {
+			continue
+		}
+
+		for _, word := range template.pattern.words {
+			unique[word] = true
+		}
+	}
+
+	words := make([]string, 0, len(unique))
+	for word := range unique {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	return words
+}
+
+// maxDistance returns the number of typos tolerated in a word of the length in runes:
+// none in short words, one in words of 4 to 6 letters and two in longer words.
+func maxDistance(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// correct returns the sentence with words replaced by the closest words of the vocabulary
+// within maxDistance, or nil if no word is corrected.
+func (sentence *sentence) correct(vocabulary []string) *sentence {
+	stems := make([]string, 0, len(sentence.stems))
+	changed := false
+	for _, stem := range sentence.stems {
+		if word, ok := closest(stem, vocabulary); ok {
+			stem, changed = word, true
+		}
+
+		stems = append(stems, stem)
+	}
+	if !changed {
+		return nil
+	}
+
+	corrected := *sentence
+	corrected.stems = stems
+	corrected.join()
+
+	return &corrected
+}
+
+// closest returns the closest word of the vocabulary to the stem if the stem is not in it.
+// Of equally close words the first one in the vocabulary is taken.
+func closest(stem string, vocabulary []string) (string, bool) {
+	if i := sort.SearchStrings(vocabulary, stem); i < len(vocabulary) && vocabulary[i] == stem {
+		return "", false
+	}
+
+	runes := []rune(stem)
+	limit := maxDistance(len(runes))
+	if limit == 0 || !isWord(runes) {
+		return "", false
+	}
+
+	best, bestDistance := "", limit+1
+	for _, word := range vocabulary {
+		length := utf8.RuneCountInString(word)
+		if length < len(runes)-limit || length > len(runes)+limit {
+			continue
+		}
+
+		if d := distance(runes, []rune(word)); d < bestDistance {
+			best, bestDistance = word, d
+		}
+	}
+
+	return best, best != ""
+}
+
+// isWord reports whether all runes are letters.
+func isWord(runes []rune) bool {
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// distance is the number of inserted, deleted, replaced and swapped adjacent runes
+// which turn one word into another, the optimal string alignment distance.
+func distance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(a)][len(b)]
+}
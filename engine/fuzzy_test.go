@@ -0,0 +1,67 @@
+package engine
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{a: "привіт", b: "привіт", distance: 0},
+		{a: "привт", b: "привіт", distance: 1},
+		{a: "привііт", b: "привіт", distance: 1},
+		{a: "привет", b: "привіт", distance: 1},
+		{a: "првиіт", b: "привіт", distance: 1},
+		{a: "фільм", b: "фльми", distance: 2},
+		{a: "", b: "так", distance: 3},
+	}
+	for _, test := range tests {
+		if d := distance([]rune(test.a), []rune(test.b)); d != test.distance {
+			t.Errorf("distance(%q, %q) = %d, want %d", test.a, test.b, d, test.distance)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	vocabulary := []string{"погода", "порадь", "привіт", "так", "фільми"}
+
+	tests := []struct {
+		stem string
+		want string
+		ok   bool
+	}{
+		{stem: "привт", want: "привіт", ok: true},
+		{stem: "пордь", want: "порадь", ok: true},
+		{stem: "фільмии", want: "фільми", ok: true},
+		// words of the vocabulary are not corrected.
+		{stem: "привіт"},
+		// short words are not corrected.
+		{stem: "тка"},
+		// too many typos.
+		{stem: "прувет"},
+		// words with digits are not corrected.
+		{stem: "привіт1"},
+	}
+	for _, test := range tests {
+		word, ok := closest(test.stem, vocabulary)
+		if word != test.want || ok != test.ok {
+			t.Errorf("closest(%q) = %q, %v, want %q, %v", test.stem, word, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	vocabulary := []string{"погода", "привіт", "як"}
+
+	corrected := newSentence("привт , як погда", nil).correct(vocabulary)
+	if corrected == nil || corrected.text != "привіт , як погода" {
+		t.Fatalf("corrected = %+v, want привіт , як погода", corrected)
+	}
+	if corrected.original(0, len("привіт")) != "привт" {
+		t.Errorf("original = %q, want привт", corrected.original(0, len("привіт")))
+	}
+
+	if corrected := newSentence("привіт , як погода", nil).correct(vocabulary); corrected != nil {
+		t.Errorf("corrected = %q, want nil", corrected.text)
+	}
+}
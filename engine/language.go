@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownLanguage indicates that there is no language with such name.
+var ErrUnknownLanguage = errors.New("unknown language")
+
+// Language defines which characters make words in templates and sentences of a corpus.
+type Language struct {
+	Name string
+	// Letters is the body of a regular expression character class of word characters.
+	Letters string
+}
+
+// Apostrophes are the apostrophe variants used inside words: ' ’ ʼ.
+const Apostrophes = `'’ʼ`
+
+var (
+	// Unicode treats letters and digits of any script as word characters.
+	Unicode = Language{Name: "unicode", Letters: `\p{L}\p{M}\p{N}` + Apostrophes + `\-`}
+	// Ukrainian treats full ukrainian alphabet, latin letters and digits as word characters.
+	Ukrainian = Language{Name: "uk", Letters: `а-щьюяєіїґА-ЩЬЮЯЄІЇҐa-zA-Z0-9` + Apostrophes + `\-`}
+	// English treats latin letters and digits as word characters.
+	English = Language{Name: "en", Letters: `a-zA-Z0-9` + Apostrophes + `\-`}
+)
+
+// languages are all known languages by name.
+var languages = map[string]Language{
+	Unicode.Name:   Unicode,
+	Ukrainian.Name: Ukrainian,
+	English.Name:   English,
+}
+
+// LanguageByName returns known language by its name.
+func LanguageByName(name string) (Language, error) {
+	language, ok := languages[name]
+	if !ok {
+		names := make([]string, 0, len(languages))
+		for name := range languages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return Language{}, fmt.Errorf("%w %q, known are %v", ErrUnknownLanguage, name, names)
+	}
+
+	return language, nil
+}
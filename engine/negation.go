@@ -0,0 +1,76 @@
+package engine
+
+// negationParticles negate the words which follow them up to the end of the clause.
+var negationParticles = map[string]bool{
+	"не":     true,
+	"ні":     true,
+	"ніколи": true,
+}
+
+// clauseBreaks end the scope of negation.
+var clauseBreaks = map[string]bool{
+	".":     true,
+	",":     true,
+	"!":     true,
+	"?":     true,
+	";":     true,
+	":":     true,
+	"а":     true,
+	"але":   true,
+	"проте": true,
+}
+
+// negators returns for every word the index of the closest negation particle before it in the same clause,
+// or -1 if the word is not negated.
+func negators(words []string) []int {
+	negators := make([]int, len(words))
+	particle := -1
+	for i, word := range words {
+		if clauseBreaks[word] {
+			particle = -1
+		}
+
+		negators[i] = particle
+		if negationParticles[word] {
+			particle = i
+		}
+	}
+
+	return negators
+}
+
+// negated reports whether a literal word of the pattern match is negated in the sentence by a particle
+// which is not a literal word of the template itself, e.g. "не порадь фільми" negates "порадь фільми",
+// while "не можу не погодитись" negates nothing.
+func (pattern *Pattern) negated(sentence *sentence) bool {
+	indexes := pattern.regex.FindStringSubmatchIndex(sentence.text)
+	if indexes == nil {
+		return false
+	}
+
+	begin, groups := pattern.body(indexes)
+
+	// literal reports whether the word is matched by the template, but not captured by its slots.
+	literal := func(word int) bool {
+		start, end := sentence.starts[word], sentence.ends[word]
+		if start < begin || end > indexes[1] {
+			return false
+		}
+
+		for i := 0; i < len(groups); i += 2 {
+			if groups[i] >= 0 && start >= groups[i] && end <= groups[i+1] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for word, particle := range sentence.negators {
+		if particle >= 0 && literal(word) && !literal(particle) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestNegated(t *testing.T) {
+	tests := []struct {
+		template string
+		mode     types.MatchMode
+		negation types.Negation
+		sentence string
+		match    bool
+		negated  bool
+	}{
+		{template: "порадь фільми", sentence: "порадь фільми", match: true},
+		{template: "порадь фільми", sentence: "не порадь фільми", match: true, negated: true},
+		{template: "порадь фільми", sentence: "не знаю, порадь фільми", match: true},
+		{template: "порадь фільми", sentence: "не проти, але порадь фільми", match: true},
+		{template: "не можу не погодитись", sentence: "не можу не погодитись", match: true},
+		{template: "люблю _", sentence: "люблю не фільми", match: true},
+		{template: "так", mode: types.MatchWhole, sentence: "так", match: true},
+		{template: "так", mode: types.MatchWhole, negation: "ні", sentence: "не так", match: true, negated: true},
+		{template: "так", mode: types.MatchWhole, negation: "ні", sentence: "не так!", match: true, negated: true},
+		// templates without negation stay anchored at the start of the sentence.
+		{template: "так", mode: types.MatchWhole, sentence: "не так"},
+		{template: "ні", mode: types.MatchWhole, sentence: "не ні"},
+		{template: "так", mode: types.MatchWhole, negation: "ні", sentence: "звісно так"},
+		{template: "дякую", mode: types.MatchPrefix, negation: types.NegationSuppress, sentence: "ні, дякую", match: false},
+		{template: "дякую", mode: types.MatchPrefix, negation: types.NegationSuppress, sentence: "ніколи не дякую вам", match: true, negated: true},
+		{template: "дякую", mode: types.MatchPrefix, sentence: "ніколи не дякую вам"},
+		{template: "привіт", mode: types.MatchPrefix, sentence: "не привіт"},
+		{template: "не знаю", mode: types.MatchWhole, negation: types.NegationSuppress, sentence: "не знаю", match: true},
+	}
+	for _, test := range tests {
+		t.Run(test.template+"/"+test.sentence, func(t *testing.T) {
+			config := DefaultConfig()
+			pattern, err := CompileTemplate(types.Template{Template: test.template, Mode: test.mode, Negation: test.negation}, nil, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sentence := newSentence(config.Normalizers.Normalize(test.sentence), nil)
+			if _, _, ok := pattern.match(sentence); ok != test.match {
+				t.Fatalf("match = %v, want %v", ok, test.match)
+			}
+			if negated := pattern.negated(sentence); negated != test.negated {
+				t.Errorf("negated = %v, want %v", negated, test.negated)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrUnknownNormalizer indicates that there is no normalizer with such name.
+var ErrUnknownNormalizer = errors.New("unknown normalizer")
+
+// Normalizer is a single step of normalization of sentences and template words.
+type Normalizer struct {
+	Name string
+	// Normalize returns the normalized text.
+	Normalize func(text string) string
+}
+
+var (
+	// NFC composes letters with combining marks, so that й typed as и and a breve matches й.
+	NFC = Normalizer{Name: "nfc", Normalize: norm.NFC.String}
+	// Lower lowercases the text.
+	Lower = Normalizer{Name: "lower", Normalize: strings.ToLower}
+	// UnifyApostrophes replaces apostrophe variants ’ ʼ ‘ ` with '.
+	UnifyApostrophes = Normalizer{Name: "apostrophes", Normalize: unifyApostrophes}
+	// RemoveQuotes replaces quotation marks with spaces.
+	RemoveQuotes = Normalizer{Name: "quotes", Normalize: removeQuotes}
+	// RemoveEmoji replaces emoji and other pictographic symbols with spaces.
+	RemoveEmoji = Normalizer{Name: "emoji", Normalize: removeEmoji}
+	// CollapseEllipses replaces the ellipsis character and runs of dots with a single dot.
+	CollapseEllipses = Normalizer{Name: "ellipsis", Normalize: collapseEllipses}
+	// CollapseRepeats replaces a letter repeated three or more times with a single one, e.g. привііііт with привіт.
+	CollapseRepeats = Normalizer{Name: "repeats", Normalize: collapseRepeats}
+	// SplitPunctuation separates punctuation marks . , ! ? from words, except marks inside of numbers.
+	SplitPunctuation = Normalizer{Name: "punctuation", Normalize: splitPunctuation}
+	// CollapseSpaces replaces runs of whitespace with a single space and trims the text.
+	CollapseSpaces = Normalizer{Name: "spaces", Normalize: collapseSpaces}
+)
+
+// normalizers are all known normalizers by name.
+var normalizers = map[string]Normalizer{
+	NFC.Name:              NFC,
+	Lower.Name:            Lower,
+	UnifyApostrophes.Name: UnifyApostrophes,
+	RemoveQuotes.Name:     RemoveQuotes,
+	RemoveEmoji.Name:      RemoveEmoji,
+	CollapseEllipses.Name: CollapseEllipses,
+	CollapseRepeats.Name:  CollapseRepeats,
+	SplitPunctuation.Name: SplitPunctuation,
+	CollapseSpaces.Name:   CollapseSpaces,
+}
+
+// NormalizerByName returns known normalizer by its name.
+func NormalizerByName(name string) (Normalizer, error) {
+	normalizer, ok := normalizers[name]
+	if !ok {
+		names := make([]string, 0, len(normalizers))
+		for name := range normalizers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return Normalizer{}, fmt.Errorf("%w %q, known are %v", ErrUnknownNormalizer, name, names)
+	}
+
+	return normalizer, nil
+}
+
+// Pipeline is an ordered list of normalizers, the text is left as it is if the pipeline is empty.
+type Pipeline []Normalizer
+
+// DefaultPipeline returns all known normalizers in the order they are meant to run.
+func DefaultPipeline() Pipeline {
+	return Pipeline{
+		NFC, Lower, UnifyApostrophes, RemoveQuotes, RemoveEmoji,
+		CollapseEllipses, CollapseRepeats, SplitPunctuation, CollapseSpaces,
+	}
+}
+
+// PipelineByNames returns the pipeline of known normalizers in the order of names.
+func PipelineByNames(names []string) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(names))
+	for _, name := range names {
+		normalizer, err := NormalizerByName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, normalizer)
+	}
+
+	return pipeline, nil
+}
+
+// Names returns names of the normalizers of the pipeline in order.
+func (pipeline Pipeline) Names() []string {
+	names := make([]string, 0, len(pipeline))
+	for _, normalizer := range pipeline {
+		names = append(names, normalizer.Name)
+	}
+
+	return names
+}
+
+// Normalize runs the normalizers of the pipeline on the text in order.
+func (pipeline Pipeline) Normalize(text string) string {
+	for _, normalizer := range pipeline {
+		text = normalizer.Normalize(text)
+	}
+
+	return text
+}
+
+var apostropheReplacer = strings.NewReplacer("’", "'", "ʼ", "'", "‘", "'", "`", "'")
+
+func unifyApostrophes(text string) string {
+	return apostropheReplacer.Replace(text)
+}
+
+var quoteReplacer = strings.NewReplacer(`"`, " ", "«", " ", "»", " ", "“", " ", "”", " ", "„", " ", "‹", " ", "›", " ")
+
+func removeQuotes(text string) string {
+	return quoteReplacer.Replace(text)
+}
+
+// isEmoji reports whether the rune is a pictographic symbol or a part of an emoji sequence,
+// such as a variation selector, a skin tone modifier or a zero width joiner.
+func isEmoji(r rune) bool {
+	return unicode.Is(unicode.So, r) || unicode.Is(unicode.Variation_Selector, r) ||
+		r == '\u200d' || (r >= 0x1f3fb && r <= 0x1f3ff)
+}
+
+func removeEmoji(text string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return ' '
+		}
+
+		return r
+	}, text)
+}
+
+func collapseEllipses(text string) string {
+	var out strings.Builder
+	dot := false
+	for _, r := range text {
+		if r == '.' || r == '…' {
+			if !dot {
+				out.WriteRune('.')
+			}
+			dot = true
+			continue
+		}
+
+		dot = false
+		out.WriteRune(r)
+	}
+
+	return out.String()
+}
+
+// repeatLimit is the number of repeats of a letter from which they are collapsed.
+const repeatLimit = 3
+
+func collapseRepeats(text string) string {
+	runes := []rune(text)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && runes[j] == runes[i] {
+			j++
+		}
+
+		if unicode.IsLetter(runes[i]) && j-i >= repeatLimit {
+			out = append(out, runes[i])
+		} else {
+			out = append(out, runes[i:j]...)
+		}
+		i = j
+	}
+
+	return string(out)
+}
+
+func splitPunctuation(text string) string {
+	runes := []rune(text)
+	var out strings.Builder
+	for i, r := range runes {
+		if !isPunctuation(r) {
+			out.WriteRune(r)
+			continue
+		}
+
+		if i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+			out.WriteRune(r)
+			continue
+		}
+
+		out.WriteString(" " + string(r) + " ")
+	}
+
+	return out.String()
+}
+
+func collapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
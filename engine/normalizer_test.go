@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		normalizer Normalizer
+		text       string
+		want       string
+	}{
+		{normalizer: NFC, text: "прив\u0456\u0438\u0306 \u0456\u0308", want: "привій ї"},
+		{normalizer: NFC, text: "\u0456\u0308", want: "\u0457"},
+		{normalizer: Lower, text: "ПРИВІТ Ґанок", want: "привіт ґанок"},
+		{normalizer: UnifyApostrophes, text: "м’ясо пʼять сім‘я `так`", want: "м'ясо п'ять сім'я 'так'"},
+		{normalizer: RemoveQuotes, text: `«фільм» "книга" „пісня“`, want: " фільм   книга   пісня "},
+		{normalizer: RemoveEmoji, text: "так😀👍🏻 ❤️ 👨\u200d👩", want: "так" + strings.Repeat(" ", 10)},
+		{normalizer: RemoveEmoji, text: "100% так: 5+5", want: "100% так: 5+5"},
+		{normalizer: CollapseEllipses, text: "ну… так.... добре.", want: "ну. так. добре."},
+		{normalizer: CollapseRepeats, text: "привііііт таак", want: "привіт таак"},
+		{normalizer: CollapseRepeats, text: "10000 ммм", want: "10000 м"},
+		{normalizer: SplitPunctuation, text: "привіт,як справи?", want: "привіт , як справи ? "},
+		{normalizer: SplitPunctuation, text: "версія 3.5 чи 3,5!", want: "версія 3.5 чи 3,5 ! "},
+		{normalizer: CollapseSpaces, text: "  привіт \t  як\nсправи  ", want: "привіт як справи"},
+	}
+	for _, test := range tests {
+		t.Run(test.normalizer.Name+"/"+test.text, func(t *testing.T) {
+			if got := test.normalizer.Normalize(test.text); got != test.want {
+				t.Errorf("%s(%q) = %q, want %q", test.normalizer.Name, test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline Pipeline
+		text     string
+		want     string
+	}{
+		{name: "default", pipeline: DefaultPipeline(), text: "  ПРИВІІІІТ!!! «Як» справи…😀", want: "привіт ! ! ! як справи ."},
+		{name: "default apostrophes", pipeline: DefaultPipeline(), text: "Пʼять м’ясних", want: "п'ять м'ясних"},
+		{name: "empty", pipeline: nil, text: " ПРИВІТ! ", want: " ПРИВІТ! "},
+		// repeats are collapsed case sensitively, so lowercasing first collapses mixed case letters.
+		{name: "lower before repeats", pipeline: Pipeline{Lower, CollapseRepeats}, text: "ТАаа", want: "та"},
+		{name: "repeats before lower", pipeline: Pipeline{CollapseRepeats, Lower}, text: "ТАаа", want: "тааа"},
+		// a split ellipsis is not collapsed to a single mark.
+		{name: "ellipsis before punctuation", pipeline: Pipeline{CollapseEllipses, SplitPunctuation, CollapseSpaces}, text: "так...", want: "так ."},
+		{name: "punctuation before ellipsis", pipeline: Pipeline{SplitPunctuation, CollapseEllipses, CollapseSpaces}, text: "так...", want: "так . . ."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.pipeline.Normalize(test.text); got != test.want {
+				t.Errorf("Normalize(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPipelineByNames(t *testing.T) {
+	pipeline, err := PipelineByNames([]string{"nfc", " lower", "spaces"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pipeline) != 3 || pipeline[0].Name != "nfc" || pipeline[1].Name != "lower" || pipeline[2].Name != "spaces" {
+		t.Errorf("pipeline = %v, want nfc, lower, spaces", pipeline)
+	}
+
+	if _, err := PipelineByNames([]string{"lower", "stem"}); !errors.Is(err, ErrUnknownNormalizer) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownNormalizer)
+	}
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnknownStemmer indicates that there is no stemmer with such name.
+var ErrUnknownStemmer = errors.New("unknown stemmer")
+
+// NoStemmer is the name which turns stemming off.
+const NoStemmer = "none"
+
+// minStem is the least number of letters left of a stemmed word.
+const minStem = 4
+
+//go:embed stemmers/*.txt
+var stemmers embed.FS
+
+// Stemmer reduces words to their stems by rules shipped as local data,
+// so that a template matches inflected forms of its words.
+type Stemmer struct {
+	Name string
+	// reflexive are suffixes removed before endings, the longest first.
+	reflexive []string
+	// endings are removed after reflexive suffixes, the longest first.
+	endings []string
+	// words are kept as they are.
+	words map[string]bool
+}
+
+// StemmerByName loads the stemmer of the language, it returns nil for NoStemmer.
+func StemmerByName(name string) (*Stemmer, error) {
+	if name == NoStemmer {
+		return nil, nil
+	}
+
+	data, err := stemmers.ReadFile("stemmers/" + name + ".txt")
+	if err != nil {
+		names := []string{NoStemmer}
+		files, _ := fs.Glob(stemmers, "stemmers/*.txt")
+		for _, file := range files {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(file, "stemmers/"), ".txt"))
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("%w %q, known are %v", ErrUnknownStemmer, name, names)
+	}
+
+	return parseStemmer(name, data)
+}
+
+// parseStemmer parses stemmer data of [reflexive], [endings] and [words] sections,
+// a single value per line, lines starting with # are comments.
+func parseStemmer(name string, data []byte) (*Stemmer, error) {
+	stemmer := &Stemmer{Name: name, words: make(map[string]bool)}
+
+	var section string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		value := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		switch {
+		case value == "" || strings.HasPrefix(value, "#"):
+		case strings.HasPrefix(value, "["):
+			section = value
+		case section == "[reflexive]":
+			stemmer.reflexive = append(stemmer.reflexive, value)
+		case section == "[endings]":
+			stemmer.endings = append(stemmer.endings, value)
+		case section == "[words]":
+			stemmer.words[value] = true
+		default:
+			return nil, fmt.Errorf("stemmer %q line %d: value out of section", name, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("stemmer %q: %w", name, err)
+	}
+
+	byLength := func(list []string) func(i, j int) bool {
+		return func(i, j int) bool {
+			return utf8.RuneCountInString(list[i]) > utf8.RuneCountInString(list[j])
+		}
+	}
+	sort.SliceStable(stemmer.reflexive, byLength(stemmer.reflexive))
+	sort.SliceStable(stemmer.endings, byLength(stemmer.endings))
+
+	return stemmer, nil
+}
+
+// Stem returns the stem of the lower case word.
+func (stemmer *Stemmer) Stem(word string) string {
+	if stemmer.words[word] {
+		return word
+	}
+
+	word = trimLongest(word, stemmer.reflexive)
+
+	return trimLongest(word, stemmer.endings)
+}
+
+// trimLongest removes the first of suffixes which leaves at least minStem letters.
+func trimLongest(word string, suffixes []string) string {
+	length := utf8.RuneCountInString(word)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && length-utf8.RuneCountInString(suffix) >= minStem {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+
+	return word
+}
@@ -0,0 +1,45 @@
+package engine
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	stemmer, err := StemmerByName("uk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		word string
+		stem string
+	}{
+		{word: "фільми", stem: "фільм"},
+		{word: "книгу", stem: "книг"},
+		{word: "погоди", stem: "погод"},
+		{word: "дякую", stem: "дяку"},
+		{word: "дякуємо", stem: "дяку"},
+		{word: "смієшся", stem: "смієш"},
+		// forms of a verb share the stem, so that a template matches them.
+		{word: "порадь", stem: "порад"},
+		{word: "порадьте", stem: "порад"},
+		// short words keep their endings.
+		{word: "знаю", stem: "знаю"},
+		{word: "кіно", stem: "кіно"},
+		{word: "яка", stem: "яка"},
+		// words of the stemmer are kept as they are.
+		{word: "такий", stem: "такий"},
+		{word: "колись", stem: "колись"},
+		{word: "якийсь", stem: "якийсь"},
+	}
+	for _, test := range tests {
+		if stem := stemmer.Stem(test.word); stem != test.stem {
+			t.Errorf("Stem(%q) = %q, want %q", test.word, stem, test.stem)
+		}
+	}
+
+	// pronouns whose сь is not reflexive are not cut down to other words.
+	for _, pair := range [][2]string{{"якась", "яка"}, {"хтось", "хто"}, {"щось", "що"}, {"колись", "коли"}, {"якийсь", "який"}, {"чомусь", "чому"}, {"кудись", "куди"}} {
+		if stemmer.Stem(pair[0]) == stemmer.Stem(pair[1]) {
+			t.Errorf("%q and %q have the same stem %q", pair[0], pair[1], stemmer.Stem(pair[0]))
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package engine
+
+import (
+	"context"
+
+	"phatic_dialogue/types"
+)
+
+// Content is a storage of the dialogue content used by the Analyser and the Builder.
+type Content interface {
+	// Corpus returns all content grouped by topic together with all entities, read at once,
+	// so that it never mixes content from before and after a change.
+	Corpus(ctx context.Context) (types.Corpus, error)
+}
@@ -0,0 +1,659 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"phatic_dialogue/types"
+)
+
+// Template language.
+//
+// Text of a template is matched literally, word by word, ignoring the amount of spaces between words.
+// Special syntax is:
+//
+//	_          a single word slot
+//	$          a slot of one or more words
+//	_:name     a named slot, $:name as well
+//	@entity    one of synonyms of the entity values, captured as a slot named by the entity
+//	[words]    optional words
+//	(a|b c)    one of alternatives, each of them is one or more words
+//	\x         the character x taken literally, e.g. \_ or \(
+//
+// Punctuation marks . , ! ? are separate optional words, since people often skip them.
+//
+// With a stemmer, literal words of templates and words of sentences are both reduced to stems,
+// so that a template matches inflected forms of its words.
+//
+// Words matched by slots are captured in the order of slots in the template.
+//
+// A template matches whole words only. By its match mode it is found anywhere in a sentence,
+// at the beginning of it, or has to take the whole sentence, except for trailing punctuation marks.
+
+// TemplateError is a syntax error in a template.
+type TemplateError struct {
+	Template string
+	// Position is the 1-based position of the wrong character in runes.
+	Position int
+	Message  string
+}
+
+// Error implements error interface.
+func (err *TemplateError) Error() string {
+	return fmt.Sprintf("template %q at %d: %s", err.Template, err.Position, err.Message)
+}
+
+// EntityLists are synonyms of entities by entity name.
+type EntityLists map[string][]types.Entity
+
+// NewEntityLists groups entity synonyms by entity name.
+func NewEntityLists(entities []types.Entity) EntityLists {
+	lists := make(EntityLists)
+	for _, entity := range entities {
+		name := strings.ToLower(entity.Entity)
+		lists[name] = append(lists[name], entity)
+	}
+
+	return lists
+}
+
+// Pattern is a compiled template.
+type Pattern struct {
+	regex *regexp.Regexp
+	// slots are names of slots in the order of their capturing groups.
+	slots []string
+	// canonical are canonical values by matched synonyms of entity slots, nil for other slots.
+	canonical []map[string]string
+	// words are literal words of the template as they are matched, stemmed if stemming is on.
+	words       []string
+	specificity int
+	// anchored reports whether the first group captures negation particles before a prefix or whole template with negation.
+	anchored bool
+}
+
+// match reports whether the pattern matches the sentence and returns captured slots
+// with words of the sentence as they were before stemming, together with the matched span of the sentence text.
+func (pattern *Pattern) match(sentence *sentence) ([]types.Slot, string, bool) {
+	indexes := pattern.regex.FindStringSubmatchIndex(sentence.text)
+	if indexes == nil {
+		return nil, "", false
+	}
+
+	begin, groups := pattern.body(indexes)
+	slots := make([]types.Slot, 0, len(pattern.slots))
+	for i, name := range pattern.slots {
+		slot := types.Slot{Name: name}
+		if start, end := groups[2*i], groups[2*i+1]; start >= 0 {
+			slot.Value = sentence.original(start, end)
+			slot.Canonical = pattern.canonical[i][sentence.text[start:end]]
+		}
+
+		slots = append(slots, slot)
+	}
+
+	return slots, strings.TrimSpace(sentence.text[begin:indexes[1]]), true
+}
+
+// body returns the start of the template match in the sentence text and indexes of slot groups,
+// skipping negation particles captured before an anchored template.
+func (pattern *Pattern) body(indexes []int) (int, []int) {
+	if pattern.anchored {
+		return indexes[3], indexes[4:]
+	}
+
+	return indexes[0], indexes[2:]
+}
+
+// String returns the regular expression the template is compiled into.
+func (pattern *Pattern) String() string {
+	return pattern.regex.String()
+}
+
+// Specificity is the length of required literal words in runes less the number of required slots,
+// so that a template with more words and less slots is more specific.
+func (pattern *Pattern) Specificity() int {
+	return pattern.specificity
+}
+
+// CompileTemplate parses the template and compiles it into a pattern
+// which matches it in a normalized sentence according to its match mode.
+// Prefix and whole templates with negation accept negation particles before them.
+// Entities referenced by the template are looked up in entity lists.
+func CompileTemplate(template types.Template, entities EntityLists, config Config) (*Pattern, error) {
+	parser := templateParser{template: template.Template, runes: []rune(template.Template), entities: entities, config: config}
+
+	sequence, err := parser.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.runes) {
+		return nil, parser.errorf(parser.pos, "unexpected %q", parser.runes[parser.pos])
+	}
+	if sequence.optional() {
+		return nil, parser.errorf(0, "template has no required words")
+	}
+
+	mode := template.Mode.OrDefault()
+	anchored := mode != types.MatchContains && template.Negation != ""
+	anchor := `^`
+	if anchored {
+		anchor = negationPrefix(config)
+	}
+
+	var pattern string
+	switch mode {
+	case types.MatchContains:
+		pattern = `(?:^|\s)` + sequence.pattern(config) + `(?:\s|$)`
+	case types.MatchPrefix:
+		pattern = anchor + sequence.pattern(config) + `(?:\s|$)`
+	case types.MatchWhole:
+		pattern = anchor + sequence.pattern(config) + `(?:\s+[.,!?])*$`
+	default:
+		return nil, parser.errorf(0, "unknown match mode %q", mode)
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, parser.errorf(0, "%v", err)
+	}
+
+	words := make([]string, 0, len(parser.words))
+	for _, word := range parser.words {
+		if config.Stemmer != nil {
+			word = config.Stemmer.Stem(word)
+		}
+
+		words = append(words, word)
+	}
+
+	return &Pattern{
+		regex:       regex,
+		slots:       parser.slots,
+		canonical:   parser.canonical,
+		words:       words,
+		specificity: sequence.literals() - sequence.slots(),
+		anchored:    anchored,
+	}, nil
+}
+
+// negationPrefix is the beginning of a prefix or whole template pattern with negation, which captures negation particles
+// before the template, so that negation of anchored templates is detected as it is for others.
+func negationPrefix(config Config) string {
+	particles := make([]string, 0, len(negationParticles))
+	for particle := range negationParticles {
+		if config.Stemmer != nil {
+			particle = config.Stemmer.Stem(particle)
+		}
+
+		particles = append(particles, regexp.QuoteMeta(particle))
+	}
+	sort.Strings(particles)
+
+	return `^((?:(?:` + strings.Join(particles, "|") + `)` + separator + `)*)`
+}
+
+// templateNode is a single element of a parsed template.
+type templateNode interface {
+	// pattern returns the regular expression of the node.
+	pattern(config Config) string
+	// optional reports whether the node could match nothing.
+	optional() bool
+	// literals returns the length in runes of literal words the node always matches.
+	literals() int
+	// slots returns the number of slots the node always matches.
+	slots() int
+}
+
+type (
+	// literalNode is a word matched as is.
+	literalNode struct {
+		word string
+	}
+
+	// punctuationNode is an optional punctuation mark.
+	punctuationNode struct {
+		mark rune
+	}
+
+	// slotNode is a single word or many words slot, captured by a group.
+	slotNode struct {
+		many bool
+		name string
+	}
+
+	// optionalNode is an optional sequence of nodes.
+	optionalNode struct {
+		sequence sequenceNode
+	}
+
+	// entityNode is one of synonyms of entity values, captured by a group.
+	entityNode struct {
+		// synonyms are stemmed words of synonyms, the longest first.
+		synonyms [][]string
+	}
+
+	// alternativesNode is one of sequences of nodes.
+	alternativesNode struct {
+		alternatives []sequenceNode
+	}
+
+	// sequenceNode is nodes separated by spaces.
+	sequenceNode []templateNode
+)
+
+// separator is the pattern of spaces between words.
+const separator = `\s+`
+
+func (node literalNode) pattern(config Config) string {
+	if config.Stemmer != nil {
+		return regexp.QuoteMeta(config.Stemmer.Stem(node.word))
+	}
+
+	return regexp.QuoteMeta(node.word)
+}
+func (node literalNode) optional() bool { return false }
+func (node literalNode) literals() int  { return len([]rune(node.word)) }
+func (node literalNode) slots() int     { return 0 }
+
+func (node punctuationNode) pattern(Config) string { return regexp.QuoteMeta(string(node.mark)) }
+func (node punctuationNode) optional() bool        { return true }
+func (node punctuationNode) literals() int         { return 0 }
+func (node punctuationNode) slots() int            { return 0 }
+
+func (node slotNode) pattern(config Config) string {
+	word := "[" + config.Language.Letters + "]+"
+	if node.many {
+		return "(" + word + "(?:" + separator + word + ")*)"
+	}
+
+	return "(" + word + ")"
+}
+func (node slotNode) optional() bool { return false }
+func (node slotNode) literals() int  { return 0 }
+func (node slotNode) slots() int     { return 1 }
+
+func (node entityNode) pattern(Config) string {
+	patterns := make([]string, 0, len(node.synonyms))
+	for _, words := range node.synonyms {
+		quoted := make([]string, 0, len(words))
+		for _, word := range words {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+
+		patterns = append(patterns, strings.Join(quoted, separator))
+	}
+
+	return "(" + strings.Join(patterns, "|") + ")"
+}
+func (node entityNode) optional() bool { return false }
+func (node entityNode) slots() int     { return 0 }
+
+// literals returns the length of the shortest synonym.
+func (node entityNode) literals() int {
+	shortest := -1
+	for _, words := range node.synonyms {
+		length := 0
+		for _, word := range words {
+			length += utf8.RuneCountInString(word)
+		}
+		if shortest < 0 || length < shortest {
+			shortest = length
+		}
+	}
+
+	return shortest
+}
+
+func (node optionalNode) pattern(config Config) string { return node.sequence.pattern(config) }
+func (node optionalNode) optional() bool               { return true }
+func (node optionalNode) literals() int                { return 0 }
+func (node optionalNode) slots() int                   { return 0 }
+
+func (node alternativesNode) pattern(config Config) string {
+	patterns := make([]string, 0, len(node.alternatives))
+	for _, alternative := range node.alternatives {
+		patterns = append(patterns, alternative.pattern(config))
+	}
+
+	return "(?:" + strings.Join(patterns, "|") + ")"
+}
+
+// optional is false, since the parser rejects alternatives which have no required word.
+func (node alternativesNode) optional() bool { return false }
+
+// literals returns the shortest literal length of alternatives.
+func (node alternativesNode) literals() int {
+	shortest := node.alternatives[0].literals()
+	for _, alternative := range node.alternatives[1:] {
+		shortest = min(shortest, alternative.literals())
+	}
+
+	return shortest
+}
+
+// slots returns the largest number of slots of alternatives.
+func (node alternativesNode) slots() int {
+	largest := 0
+	for _, alternative := range node.alternatives {
+		largest = max(largest, alternative.slots())
+	}
+
+	return largest
+}
+
+// pattern joins nodes with separators, keeping separators of optional nodes
+// inside of them, so that a skipped node leaves no extra spaces behind.
+func (sequence sequenceNode) pattern(config Config) string {
+	var builder strings.Builder
+	afterRequired := false
+	for _, node := range sequence {
+		switch {
+		case !node.optional():
+			if afterRequired {
+				builder.WriteString(separator)
+			}
+			builder.WriteString(node.pattern(config))
+			afterRequired = true
+		case afterRequired:
+			builder.WriteString("(?:" + separator + node.pattern(config) + ")?")
+		default:
+			builder.WriteString("(?:" + node.pattern(config) + separator + ")?")
+		}
+	}
+
+	return builder.String()
+}
+
+func (sequence sequenceNode) optional() bool {
+	for _, node := range sequence {
+		if !node.optional() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (sequence sequenceNode) literals() int {
+	total := 0
+	for _, node := range sequence {
+		total += node.literals()
+	}
+
+	return total
+}
+
+func (sequence sequenceNode) slots() int {
+	total := 0
+	for _, node := range sequence {
+		total += node.slots()
+	}
+
+	return total
+}
+
+// templateParser is a recursive descent parser of the template language.
+type templateParser struct {
+	template string
+	runes    []rune
+	pos      int
+	entities EntityLists
+	config   Config
+
+	// slots are names of parsed slots in their order.
+	slots []string
+	// canonical are canonical values by synonyms of parsed slots, nil for slots which are not entities.
+	canonical []map[string]string
+	// words are parsed literal words.
+	words []string
+}
+
+// isPunctuation reports whether the rune is an optional punctuation mark.
+func isPunctuation(r rune) bool {
+	return r == '.' || r == ',' || r == '!' || r == '?'
+}
+
+// isSpecial reports whether the rune ends a literal word.
+func isSpecial(r rune) bool {
+	return unicode.IsSpace(r) || isPunctuation(r) || strings.ContainsRune(`_$@[]()|\`, r)
+}
+
+// parseSequence parses nodes until the end of template or a closing bracket or alternative separator.
+func (parser *templateParser) parseSequence() (sequenceNode, error) {
+	var sequence sequenceNode
+	for parser.pos < len(parser.runes) {
+		r := parser.runes[parser.pos]
+		switch {
+		case unicode.IsSpace(r):
+			parser.pos++
+		case r == ']' || r == ')' || r == '|':
+			return sequence, nil
+		case isPunctuation(r):
+			sequence = append(sequence, punctuationNode{mark: r})
+			parser.pos++
+		case r == '_' || r == '$':
+			node, err := parser.parseSlot()
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, node)
+		case r == '@':
+			node, err := parser.parseEntity()
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, node)
+		case r == '[':
+			node, err := parser.parseOptional()
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, node)
+		case r == '(':
+			node, err := parser.parseAlternatives()
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, node)
+		default:
+			node, err := parser.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+
+			sequence = append(sequence, node)
+		}
+	}
+
+	return sequence, nil
+}
+
+// parseSlot parses _ or $ with an optional :name.
+func (parser *templateParser) parseSlot() (templateNode, error) {
+	node := slotNode{many: parser.runes[parser.pos] == '$'}
+	parser.pos++
+
+	if parser.pos < len(parser.runes) && parser.runes[parser.pos] == ':' {
+		start := parser.pos
+		parser.pos++
+		for parser.pos < len(parser.runes) && isNameRune(parser.runes[parser.pos]) {
+			parser.pos++
+		}
+		if parser.pos == start+1 {
+			return nil, parser.errorf(start, "empty slot name")
+		}
+
+		node.name = string(parser.runes[start+1 : parser.pos])
+	}
+
+	parser.slots = append(parser.slots, node.name)
+	parser.canonical = append(parser.canonical, nil)
+
+	return node, nil
+}
+
+// parseEntity parses @entity.
+func (parser *templateParser) parseEntity() (templateNode, error) {
+	start := parser.pos
+	parser.pos++
+	for parser.pos < len(parser.runes) && isNameRune(parser.runes[parser.pos]) {
+		parser.pos++
+	}
+	if parser.pos == start+1 {
+		return nil, parser.errorf(start, "empty entity name")
+	}
+
+	name := string(parser.runes[start+1 : parser.pos])
+	entities, ok := parser.entities[name]
+	if !ok {
+		return nil, parser.errorf(start, "unknown entity %q", name)
+	}
+
+	node := entityNode{}
+	canonical := make(map[string]string, len(entities))
+	for _, entity := range entities {
+		words := strings.Fields(parser.config.Normalizers.Normalize(entity.Synonym))
+		parser.words = append(parser.words, words...)
+		if parser.config.Stemmer != nil {
+			for i, word := range words {
+				words[i] = parser.config.Stemmer.Stem(word)
+			}
+		}
+
+		text := strings.Join(words, " ")
+		if _, ok := canonical[text]; ok || len(words) == 0 {
+			continue
+		}
+
+		canonical[text] = strings.ToLower(entity.Value)
+		node.synonyms = append(node.synonyms, words)
+	}
+
+	// the longest synonyms go first, so that a synonym is not matched by its beginning.
+	sort.SliceStable(node.synonyms, func(i, j int) bool {
+		return len(strings.Join(node.synonyms[i], " ")) > len(strings.Join(node.synonyms[j], " "))
+	})
+
+	parser.slots = append(parser.slots, name)
+	parser.canonical = append(parser.canonical, canonical)
+
+	return node, nil
+}
+
+// isNameRune reports whether the rune could be a part of a slot name.
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// parseOptional parses [words].
+func (parser *templateParser) parseOptional() (templateNode, error) {
+	start := parser.pos
+	parser.pos++
+
+	sequence, err := parser.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos >= len(parser.runes) {
+		return nil, parser.errorf(start, `"[" is not closed`)
+	}
+	if parser.runes[parser.pos] != ']' {
+		return nil, parser.errorf(parser.pos, "unexpected %q", parser.runes[parser.pos])
+	}
+	if len(sequence) == 0 {
+		return nil, parser.errorf(start, "empty optional words")
+	}
+	// optional words inside of optional words keep their own separators, which would be doubled.
+	if sequence.optional() {
+		return nil, parser.errorf(start, "optional words have no required word")
+	}
+	parser.pos++
+
+	return optionalNode{sequence: sequence}, nil
+}
+
+// parseAlternatives parses (a|b c).
+func (parser *templateParser) parseAlternatives() (templateNode, error) {
+	start := parser.pos
+	parser.pos++
+
+	var node alternativesNode
+	for {
+		alternativeStart := parser.pos
+		sequence, err := parser.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		if parser.pos >= len(parser.runes) {
+			return nil, parser.errorf(start, `"(" is not closed`)
+		}
+		if parser.runes[parser.pos] == ']' {
+			return nil, parser.errorf(parser.pos, "unexpected %q", parser.runes[parser.pos])
+		}
+		if len(sequence) == 0 {
+			return nil, parser.errorf(alternativeStart, "empty alternative")
+		}
+		// an optional alternative makes alternatives optional, keeping its separators would double them.
+		if sequence.optional() {
+			return nil, parser.errorf(alternativeStart, "alternative has no required word, make the alternatives optional instead")
+		}
+
+		node.alternatives = append(node.alternatives, sequence)
+
+		closing := parser.runes[parser.pos] == ')'
+		parser.pos++
+		if closing {
+			return node, nil
+		}
+	}
+}
+
+// parseLiteral parses a word, unescaping escaped characters.
+func (parser *templateParser) parseLiteral() (templateNode, error) {
+	var word strings.Builder
+	for parser.pos < len(parser.runes) {
+		r := parser.runes[parser.pos]
+		if r == '\\' {
+			if parser.pos+1 >= len(parser.runes) {
+				return nil, parser.errorf(parser.pos, "nothing to escape")
+			}
+
+			word.WriteRune(parser.runes[parser.pos+1])
+			parser.pos += 2
+			continue
+		}
+		if isSpecial(r) {
+			break
+		}
+
+		word.WriteRune(r)
+		parser.pos++
+	}
+
+	// the word is normalized the way sentences are, which may split it or leave nothing of it.
+	words := strings.Fields(parser.config.Normalizers.Normalize(word.String()))
+	parser.words = append(parser.words, words...)
+	if len(words) == 1 {
+		return literalNode{word: words[0]}, nil
+	}
+
+	sequence := make(sequenceNode, 0, len(words))
+	for _, word := range words {
+		sequence = append(sequence, literalNode{word: word})
+	}
+
+	return sequence, nil
+}
+
+// errorf returns TemplateError at the rune position.
+func (parser *templateParser) errorf(pos int, format string, args ...any) error {
+	return &TemplateError{Template: parser.template, Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"phatic_dialogue/types"
+)
+
+func TestCompileTemplateErrors(t *testing.T) {
+	entities := NewEntityLists([]types.Entity{{Entity: "city", Value: "Київ", Synonym: "києві"}})
+
+	tests := []struct {
+		template string
+		mode     types.MatchMode
+		position int
+		message  string
+	}{
+		{template: "привіт [як", position: 8, message: `"[" is not closed`},
+		{template: "привіт (як|що", position: 8, message: `"(" is not closed`},
+		{template: "привіт ]", position: 8, message: `unexpected ']'`},
+		{template: "(як|) справи", position: 5, message: "empty alternative"},
+		{template: "a (b|[c]) d", position: 6, message: "alternative has no required word, make the alternatives optional instead"},
+		{template: "a [[b]] d", position: 3, message: "optional words have no required word"},
+		{template: "a [] d", position: 3, message: "empty optional words"},
+		{template: "[привіт] [,]", position: 10, message: "optional words have no required word"},
+		{template: "[привіт]", position: 1, message: "template has no required words"},
+		{template: "живу в _:", position: 9, message: "empty slot name"},
+		{template: "живу в @", position: 8, message: "empty entity name"},
+		{template: "живу в @town", position: 8, message: `unknown entity "town"`},
+		{template: `привіт\`, position: 7, message: "nothing to escape"},
+		{template: "привіт", mode: "middle", position: 1, message: `unknown match mode "middle"`},
+	}
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			_, err := CompileTemplate(types.Template{Template: test.template, Mode: test.mode}, entities, DefaultConfig())
+
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("error = %v, want TemplateError", err)
+			}
+			if templateErr.Position != test.position || templateErr.Message != test.message {
+				t.Errorf("error at %d %q, want at %d %q", templateErr.Position, templateErr.Message, test.position, test.message)
+			}
+		})
+	}
+}
+
+func TestCompileTemplateMatch(t *testing.T) {
+	entities := NewEntityLists([]types.Entity{
+		{Entity: "city", Value: "Київ", Synonym: "києві"},
+		{Entity: "city", Value: "Київ", Synonym: "столиці україни"},
+		{Entity: "city", Value: "Львів", Synonym: "львові"},
+	})
+
+	tests := []struct {
+		template string
+		mode     types.MatchMode
+		sentence string
+		match    bool
+		slots    []types.Slot
+	}{
+		// match modes.
+		{template: "привіт", sentence: "ну привіт усім", match: true},
+		{template: "привіт", sentence: "привітання"},
+		{template: "привіт", mode: types.MatchPrefix, sentence: "привіт усім", match: true},
+		{template: "привіт", mode: types.MatchPrefix, sentence: "ну привіт"},
+		{template: "привіт", mode: types.MatchWhole, sentence: "привіт!!", match: true},
+		{template: "привіт", mode: types.MatchWhole, sentence: "привіт усім"},
+		// optional words and punctuation.
+		{template: "як [у вас] справи?", sentence: "як справи", match: true},
+		{template: "як [у вас] справи?", sentence: "як у вас справи?", match: true},
+		{template: "як [у вас] справи", sentence: "як у справи"},
+		{template: "a [b [c] d] e", sentence: "a e", match: true},
+		{template: "a [b [c] d] e", sentence: "a b d e", match: true},
+		{template: "a [b [c] d] e", sentence: "a b c d e", match: true},
+		{template: "a [b [c] d] e", sentence: "a c e"},
+		// alternatives.
+		{template: "a (b|c d) e", sentence: "a b e", match: true},
+		{template: "a (b|c d) e", sentence: "a c d e", match: true},
+		{template: "a (b|c d) e", sentence: "a c e"},
+		{template: "a [(b|c)] d", sentence: "a d", match: true},
+		{template: "a [(b|c)] d", sentence: "a c d", match: true},
+		{template: "a (b [c]|d) e", sentence: "a b c e", match: true},
+		{template: "a (b [c]|d) e", sentence: "a b e", match: true},
+		{template: "a (b [(c|d)] e|f) g", sentence: "a b d e g", match: true},
+		// escapes.
+		{template: `\(a\)`, sentence: "(a)", match: true},
+		// slots and entities.
+		{
+			template: "мене звати _:name", sentence: "Мене звати Олег", match: true,
+			slots: []types.Slot{{Name: "name", Value: "олег"}},
+		},
+		{
+			template: "порадь $ про _", sentence: "порадь книгу або фільм про космос", match: true,
+			slots: []types.Slot{{Value: "книгу або фільм"}, {Value: "космос"}},
+		},
+		{
+			template: "живу в @city", sentence: "живу в столиці україни", match: true,
+			slots: []types.Slot{{Name: "city", Value: "столиці україни", Canonical: "київ"}},
+		},
+		{template: "живу в @city", sentence: "живу в одесі"},
+	}
+	for _, test := range tests {
+		t.Run(test.template+"/"+test.sentence, func(t *testing.T) {
+			config := DefaultConfig()
+			pattern, err := CompileTemplate(types.Template{Template: test.template, Mode: test.mode}, entities, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			slots, _, ok := pattern.match(newSentence(config.Normalizers.Normalize(test.sentence), nil))
+			if ok != test.match {
+				t.Fatalf("match = %v, want %v, pattern %s", ok, test.match, pattern)
+			}
+			if test.slots != nil && !reflect.DeepEqual(slots, test.slots) {
+				t.Errorf("slots = %+v, want %+v", slots, test.slots)
+			}
+		})
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	tests := []struct {
+		template    string
+		specificity int
+	}{
+		{template: "привіт", specificity: 6},
+		{template: "привіт [друже]", specificity: 6},
+		{template: "мене звати _", specificity: 8},
+		{template: "(так|звісно) $", specificity: 2},
+	}
+	for _, test := range tests {
+		pattern, err := CompileTemplate(types.Template{Template: test.template}, nil, DefaultConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pattern.Specificity() != test.specificity {
+			t.Errorf("specificity of %q = %d, want %d", test.template, pattern.Specificity(), test.specificity)
+		}
+	}
+}
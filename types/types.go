@@ -3,25 +3,146 @@ package types
 type (
 	Topic string
 
+	// MatchMode defines which part of a sentence a template should match.
+	MatchMode string
+
+	// Negation defines what happens to a template match negated by a particle like "не":
+	// it is empty if negation is ignored, NegationSuppress if the match is dropped,
+	// otherwise it is the name of the counterpart topic which the match is routed to.
+	Negation string
+
 	SingleInsert struct {
+		ID    int
 		Word  string
 		Topic Topic
 	}
 
 	GroupInsert struct {
+		ID    int
 		Words string
 		Topic Topic
 	}
 
 	Template struct {
+		ID       int
 		Template string
 		Topic    Topic
+		Mode     MatchMode
+		// Priority is added to the score of the template matches, it is 0 by default.
+		Priority int
+		// Exact turns off fuzzy matching of the template, so that it does not match words with typos.
+		Exact bool
+		// Negation is what happens when words of the template are negated in a sentence.
+		Negation Negation
+	}
+
+	// Match is a template found in a sentence.
+	Match struct {
+		Topic    Topic
+		Template Template
+		// Score is the specificity of the match, more specific matches have higher scores.
+		Score int
+		// Slots are values of template slots in their order in the template.
+		Slots []Slot
+		// Input is the sentence as the user wrote it.
+		Input string
+		// Fuzzy reports whether the template matched only after correcting typos of the sentence.
+		Fuzzy bool
+		// Negated reports whether the match is routed from the template topic to its negated counterpart.
+		Negated bool
+		// Confidence is the probability of the topic given by the classifier when no template matched,
+		// it is 0 for template matches.
+		Confidence float64
+	}
+
+	// Slot is a part of a sentence captured by a template slot.
+	Slot struct {
+		// Name is the name of the slot, it is empty for positional slots.
+		Name string
+		// Value is empty if the slot is in optional words which are not matched.
+		Value string
+		// Canonical is the canonical value of the entity matched by an entity slot.
+		Canonical string
+	}
+
+	// Entity is a synonym of a canonical value of the named entity list,
+	// the canonical value is a synonym of itself.
+	Entity struct {
+		ID      int
+		Entity  string
+		Value   string
+		Synonym string
 	}
 
 	Answer struct {
+		ID     int
 		Answer string
 		Topic  Topic
 	}
+
+	// Example is a labelled user sentence of the topic used to train the topic classifier.
+	Example struct {
+		ID      int
+		Example string
+		Topic   Topic
+	}
+
+	// Content is all dialogue data of a single topic.
+	Content struct {
+		Topic         Topic
+		Templates     []Template
+		Answers       []string
+		SingleInserts []string
+		GroupInserts  []string
+		// Examples are sentences of the topic which templates may not match, the classifier learns from them.
+		Examples []string
+	}
+
+	// Corpus is all dialogue data: contents of topics and entities referenced by their templates.
+	Corpus struct {
+		Contents []Content
+		Entities []Entity
+	}
 )
 
 const UnknownTopic Topic = "unknown_topic"
+
+const (
+	// MatchContains matches template anywhere in a sentence on word boundaries, it is the default.
+	MatchContains MatchMode = "contains"
+	// MatchPrefix matches template at the beginning of a sentence.
+	MatchPrefix MatchMode = "prefix"
+	// MatchWhole matches template against the whole sentence.
+	MatchWhole MatchMode = "whole"
+)
+
+// NegationSuppress drops template matches which are negated.
+const NegationSuppress Negation = "suppress"
+
+// Topic returns the counterpart topic of the negation, if the negation routes to one.
+func (negation Negation) Topic() (Topic, bool) {
+	if negation == "" || negation == NegationSuppress {
+		return "", false
+	}
+
+	return Topic(negation), true
+}
+
+// Valid reports whether the mode is known, empty mode is the default one.
+func (mode MatchMode) Valid() bool {
+	switch mode {
+	case "", MatchContains, MatchPrefix, MatchWhole:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrDefault returns the mode, or MatchContains if it is empty.
+func (mode MatchMode) OrDefault() MatchMode {
+	if mode == "" {
+		return MatchContains
+	}
+
+	return mode
+}